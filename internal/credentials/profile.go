@@ -0,0 +1,149 @@
+package credentials
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profile holds the provider settings of one named profile in the shared credentials file.
+type Profile struct {
+	Host                    string `yaml:"host"`
+	Region                  string `yaml:"region"`
+	Token                   string `yaml:"token"`
+	TokenFile               string `yaml:"token_file"`
+	PollingIntervalDuration string `yaml:"polling_interval_duration"`
+	PollingTimeoutDuration  string `yaml:"polling_timeout_duration"`
+}
+
+// DefaultCredentialsFile returns ~/.config/solace/credentials
+func DefaultCredentialsFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine the home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "solace", "credentials"), nil
+}
+
+// LoadProfile reads the named profile from a credentials file. The file is either YAML with the
+// profile names as top level keys, or INI with one section per profile:
+//
+//	[prod]
+//	host = https://api.solacecloud.eu
+//	token_file = ~/.config/solace/prod-token
+func LoadProfile(path string, name string) (*Profile, error) {
+	path = ExpandHome(path)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read credentials file: %w", err)
+	}
+
+	var profiles map[string]Profile
+	if isINI(path, content) {
+		profiles, err = parseINI(content)
+	} else {
+		profiles, err = parseYAML(content)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse credentials file %s: %w", path, err)
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found in credentials file %s", name, path)
+	}
+	if profile.Token != "" && profile.TokenFile != "" {
+		return nil, fmt.Errorf("profile %q sets both token and token_file", name)
+	}
+	profile.TokenFile = ExpandHome(profile.TokenFile)
+	return &profile, nil
+}
+
+// ExpandHome replaces a leading ~/ with the home directory, other paths are returned unchanged
+func ExpandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
+
+// isINI decides on the file extension, files without extension are INI if they start with a section
+func isINI(path string, content []byte) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return false
+	case ".ini":
+		return true
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		return line[0] == '['
+	}
+	return false
+}
+
+func parseYAML(content []byte) (map[string]Profile, error) {
+	profiles := map[string]Profile{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&profiles); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+func parseINI(content []byte) (map[string]Profile, error) {
+	profiles := map[string]Profile{}
+	var section string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' && line[len(line)-1] == ']' {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			profiles[section] = Profile{}
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNo)
+		}
+		if section == "" {
+			return nil, fmt.Errorf("line %d: key outside of a [profile] section", lineNo)
+		}
+		profile := profiles[section]
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch strings.TrimSpace(key) {
+		case "host":
+			profile.Host = value
+		case "region":
+			profile.Region = value
+		case "token":
+			profile.Token = value
+		case "token_file":
+			profile.TokenFile = value
+		case "polling_interval_duration":
+			profile.PollingIntervalDuration = value
+		case "polling_timeout_duration":
+			profile.PollingTimeoutDuration = value
+		default:
+			return nil, fmt.Errorf("line %d: unknown key %q", lineNo, strings.TrimSpace(key))
+		}
+		profiles[section] = profile
+	}
+	return profiles, scanner.Err()
+}
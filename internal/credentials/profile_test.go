@@ -0,0 +1,87 @@
+package credentials
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadProfileINI(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "credentials")
+	assert.NoError(t, os.WriteFile(file, []byte(`
+# shared solace credentials
+[dev]
+host = http://localhost:8091
+token = "dev-token"
+polling_interval_duration = 2s
+
+[prod]
+region = eu
+token_file = /run/secrets/solace
+`), 0600))
+
+	profile, err := LoadProfile(file, "dev")
+	assert.NoError(t, err)
+	assert.Equal(t, Profile{Host: "http://localhost:8091", Token: "dev-token", PollingIntervalDuration: "2s"}, *profile)
+
+	profile, err = LoadProfile(file, "prod")
+	assert.NoError(t, err)
+	assert.Equal(t, Profile{Region: "eu", TokenFile: "/run/secrets/solace"}, *profile)
+
+	_, err = LoadProfile(file, "test")
+	assert.ErrorContains(t, err, `profile "test" not found`)
+}
+
+func TestLoadProfileYAML(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "credentials.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte(`
+dev:
+  host: http://localhost:8091
+  token: dev-token
+prod:
+  region: eu
+  polling_timeout_duration: 45m
+`), 0600))
+
+	profile, err := LoadProfile(file, "prod")
+	assert.NoError(t, err)
+	assert.Equal(t, Profile{Region: "eu", PollingTimeoutDuration: "45m"}, *profile)
+}
+
+func TestLoadProfileInvalid(t *testing.T) {
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "typo")
+	assert.NoError(t, os.WriteFile(file, []byte("[dev]\nhots = http://localhost:8091\n"), 0600))
+	_, err := LoadProfile(file, "dev")
+	assert.ErrorContains(t, err, `unknown key "hots"`)
+
+	file = filepath.Join(dir, "typo.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte("dev:\n  hots: http://localhost:8091\n"), 0600))
+	_, err = LoadProfile(file, "dev")
+	assert.Error(t, err)
+
+	file = filepath.Join(dir, "both")
+	assert.NoError(t, os.WriteFile(file, []byte("[dev]\ntoken = a\ntoken_file = b\n"), 0600))
+	_, err = LoadProfile(file, "dev")
+	assert.ErrorContains(t, err, "both token and token_file")
+
+	_, err = LoadProfile(filepath.Join(dir, "missing"), "dev")
+	assert.Error(t, err)
+}
+
+func TestLoadProfileExpandsHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	assert.NoError(t, os.WriteFile(filepath.Join(home, "credentials"), []byte(`
+[dev]
+host = http://localhost:8091
+token_file = ~/.config/solace/dev-token
+`), 0600))
+
+	profile, err := LoadProfile("~/credentials", "dev")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".config", "solace", "dev-token"), profile.TokenFile)
+}
@@ -0,0 +1,150 @@
+/*
+Package credentials provides the sources for the MissionControl bearer token.
+*/
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokens are refreshed a bit before they actually expire
+const expirySkew = time.Minute
+
+// TokenSource supplies the bearer token for MissionControl API calls.
+type TokenSource interface {
+	// Token returns a valid token, fetching a fresh one if required
+	Token(ctx context.Context) (string, error)
+	// Invalidate forces the next Token call to fetch a fresh token, e.g. after a 401 response
+	Invalidate()
+}
+
+// StaticTokenSource always returns the same token (HCL attribute or environment variable).
+type StaticTokenSource struct {
+	token string
+}
+
+func NewStaticTokenSource(token string) *StaticTokenSource {
+	return &StaticTokenSource{token: token}
+}
+
+func (s *StaticTokenSource) Token(_ context.Context) (string, error) {
+	return s.token, nil
+}
+
+func (s *StaticTokenSource) Invalidate() {}
+
+// FileTokenSource reads the token from a file, the file is read again when it has been modified
+// or the token was invalidated. This supports tokens rotated by e.g. a vault agent.
+type FileTokenSource struct {
+	path    string
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+}
+
+func NewFileTokenSource(path string) *FileTokenSource {
+	return &FileTokenSource{path: path}
+}
+
+func (s *FileTokenSource) Token(_ context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read bearer token file: %w", err)
+	}
+	if s.token != "" && info.ModTime().Equal(s.modTime) {
+		return s.token, nil
+	}
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read bearer token file: %w", err)
+	}
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("bearer token file %s is empty", s.path)
+	}
+	s.token = token
+	s.modTime = info.ModTime()
+	return s.token, nil
+}
+
+func (s *FileTokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+}
+
+// CommandTokenSource runs an external credential helper and uses its stdout as token.
+// The helper may either print the plain token, or a JSON object like
+//
+//	{"token": "...", "expiresAt": "2025-04-01T12:00:00Z"}
+//
+// in which case the token is cached until shortly before it expires.
+type CommandTokenSource struct {
+	args      []string
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time // zero: valid until invalidated
+}
+
+func NewCommandTokenSource(args []string) *CommandTokenSource {
+	return &CommandTokenSource{args: args}
+}
+
+// commandOutput is the JSON format credential helpers may print
+type commandOutput struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func (s *CommandTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && (s.expiresAt.IsZero() || time.Now().Add(expirySkew).Before(s.expiresAt)) {
+		return s.token, nil
+	}
+	if len(s.args) == 0 {
+		return "", fmt.Errorf("bearer token command is empty")
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.args[0], s.args[1:]...) // #nosec G204 -- the command is configured by the practitioner
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("bearer token command %s failed: %w: %s", s.args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	out := bytes.TrimSpace(stdout.Bytes())
+	var parsed commandOutput
+	if len(out) > 0 && out[0] == '{' {
+		if err := json.Unmarshal(out, &parsed); err != nil {
+			return "", fmt.Errorf("cannot parse output of bearer token command %s: %w", s.args[0], err)
+		}
+	} else {
+		parsed.Token = string(out)
+	}
+	if parsed.Token == "" {
+		return "", fmt.Errorf("bearer token command %s returned no token", s.args[0])
+	}
+	s.token = parsed.Token
+	s.expiresAt = parsed.ExpiresAt
+	return s.token, nil
+}
+
+func (s *CommandTokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+}
@@ -0,0 +1,51 @@
+package credentials
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileTokenSource(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("token1\n"), 0600))
+
+	src := NewFileTokenSource(tokenFile)
+	token, err := src.Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "token1", token)
+
+	// rotated token is picked up after invalidation
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("token2"), 0600))
+	src.Invalidate()
+	token, err = src.Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "token2", token)
+}
+
+func TestCommandTokenSource(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	src := NewCommandTokenSource([]string{"sh", "-c", "echo plain-token"})
+	token, err := src.Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "plain-token", token)
+
+	// expired tokens are fetched again on every call
+	expired := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	src = NewCommandTokenSource([]string{"sh", "-c", `echo "{\"token\":\"json-token\",\"expiresAt\":\"` + expired + `\"}"`})
+	token, err = src.Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "json-token", token)
+	assert.Equal(t, expired, src.expiresAt.Format(time.RFC3339))
+
+	src = NewCommandTokenSource([]string{"sh", "-c", "exit 3"})
+	_, err = src.Token(context.Background())
+	assert.ErrorContains(t, err, "failed")
+}
@@ -13,7 +13,10 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,16 +25,23 @@ import (
 /* Fakeserver represents a HTTP server with objects to hold and return*/
 type Fakeserver struct {
 	server  *http.Server
+	mu      sync.Mutex // guards objects, terraform calls in parallel
 	objects map[string]ServiceInfo
-	debug   bool
-	running bool
-	baseSid int
+	// deletion time of the deleted services, their delete operation finishes after a delay
+	deletions map[string]time.Time
+	debug     bool
+	running   bool
+	baseSid   int
+	// TLS key pair, empty means plain http
+	tlsCertFile string
+	tlsKeyFile  string
 }
 
 type ServiceInfo struct {
 	ID                          string
 	ServiceClassId              string
 	DatacenterId                string
+	EnvironmentId               string
 	Name                        string
 	State                       string
 	MsgVpnName                  string
@@ -53,13 +63,18 @@ func NewFakeServer(iPort int, iObjects map[string]ServiceInfo, iStart bool, iDeb
 	serverMux := http.NewServeMux()
 
 	svr := &Fakeserver{
-		debug:   iDebug,
-		objects: iObjects,
-		running: false,
-		baseSid: iBaseSid, // 0 means generate uuids
+		debug:     iDebug,
+		objects:   iObjects,
+		deletions: map[string]time.Time{},
+		running:   false,
+		baseSid:   iBaseSid, // 0 means generate uuids
 	}
 
 	serverMux.HandleFunc("/api/v2/missionControl/", svr.handleBrokerServices)
+	serverMux.HandleFunc("/api/v2/missionControl/defaultBrokerVersions", svr.handleVersions)
+	serverMux.HandleFunc("/api/v2/missionControl/datacenters", svr.handleCatalog)
+	serverMux.HandleFunc("/api/v2/missionControl/datacenters/", svr.handleCatalog)
+	serverMux.HandleFunc("/api/v2/missionControl/serviceClasses", svr.handleCatalog)
 	// subtrees are also handled
 	// NOTE: the trailing slash will be added automatically to the URL even when not given
 	apiObjectServer := &http.Server{
@@ -84,8 +99,22 @@ func (svr *Fakeserver) SetBaseSid(sid int) {
 	log.Printf("fakeserver: setting baseSid to %d\n", svr.baseSid)
 }
 
+/*SetTLS makes the server serve https with the given PEM key pair, call before starting the server*/
+func (svr *Fakeserver) SetTLS(certFile string, keyFile string) {
+	svr.tlsCertFile = certFile
+	svr.tlsKeyFile = keyFile
+}
+
+/*ListenAndServe serves http or https (when SetTLS has been called), blocking*/
+func (svr *Fakeserver) ListenAndServe() error {
+	if svr.tlsCertFile != "" {
+		return svr.server.ListenAndServeTLS(svr.tlsCertFile, svr.tlsKeyFile)
+	}
+	return svr.server.ListenAndServe()
+}
+
 func (svr *Fakeserver) safeServe() {
-	err := svr.server.ListenAndServe()
+	err := svr.ListenAndServe()
 	if err != nil {
 		log.Printf("fakeserver: serving ended: %s\n", err)
 	}
@@ -126,7 +155,7 @@ func (svr *Fakeserver) parseRequest(r *http.Request, parts *[]string) ([]byte, e
 		return nil, err
 	}
 
-	/** we don't handle bearer token right now */
+	/** we don't validate the bearer token, only check it is present (see authorized) */
 
 	if svr.debug {
 		log.Printf("fakeserver: Received request: %+v\n", r)
@@ -153,6 +182,119 @@ func (svr *Fakeserver) parseRequest(r *http.Request, parts *[]string) ([]byte, e
 	return b, nil
 }
 
+// authorized checks the bearer token, it must be present and the token "invalid" is rejected so tests can provoke a 401
+func (svr *Fakeserver) authorized(w http.ResponseWriter, r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") || auth == "Bearer invalid" {
+		if svr.debug {
+			log.Printf("fakeserver: Unauthorized request: %s %s", r.Method, r.URL.Path)
+		}
+		http.Error(w, "{\"message\":\"Invalid or expired token\",\"errorId\":\"401\"}", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+func (svr *Fakeserver) handleVersions(w http.ResponseWriter, r *http.Request) {
+	if !svr.authorized(w, r) {
+		return
+	}
+	if r.Method != "GET" {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	result := map[string]interface{}{
+		"data": map[string]interface{}{
+			"defaultEventBrokerVersion":   "1.0.0",
+			"latestK8sEventBrokerVersion": "1.2.3",
+			"type":                        "eventBrokerVersions",
+		},
+		"meta": map[string]interface{}{
+			"additionalProp": map[string]interface{}{},
+		},
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("fakeserver: failed to marshal result: %s\n", err)
+		return
+	}
+	w.Header().Add("Content-Type", "json")
+	_, err2 := w.Write(b)
+	if err2 != nil {
+		log.Printf("fakeserver: failed to write result: %s\n", err)
+	}
+}
+
+// catalog of the fakeserver, every datacenter offers all versions
+var (
+	catalogDatacenters    = []string{"aks-germanywestcentral", "eks-eu-central-1a"}
+	catalogServiceClasses = []string{"DEVELOPER", "ENTERPRISE_250_STANDALONE", "ENTERPRISE_1K_STANDALONE", "ENTERPRISE_250_HIGHAVAILABILITY"}
+	catalogVersions       = []string{"1.0.0", "1.2.3"}
+)
+
+func (svr *Fakeserver) handleCatalog(w http.ResponseWriter, r *http.Request) {
+	if !svr.authorized(w, r) {
+		return
+	}
+	if r.Method != "GET" {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	parts := strings.Split(strings.TrimSuffix(r.URL.Path, "/"), "/")
+	data := []interface{}{}
+	switch {
+	case len(parts) == 5 && parts[4] == "datacenters":
+		for _, id := range catalogDatacenters {
+			data = append(data, map[string]interface{}{
+				"id":                      id,
+				"name":                    id,
+				"available":               true,
+				"datacenterType":          "dedicated",
+				"supportedServiceClasses": catalogServiceClasses,
+				"type":                    "datacenter",
+			})
+		}
+	case len(parts) == 5 && parts[4] == "serviceClasses":
+		for _, id := range catalogServiceClasses {
+			data = append(data, map[string]interface{}{
+				"id":   id,
+				"name": id,
+				"type": "serviceClass",
+			})
+		}
+	case len(parts) == 7 && parts[4] == "datacenters" && parts[6] == "eventBrokerServiceVersions":
+		found := false
+		for _, id := range catalogDatacenters {
+			found = found || id == parts[5]
+		}
+		if !found {
+			http.Error(w, fmt.Sprintf("{\"message\":\"Could not find datacenter with id %s\",\"errorId\":\"43\"}", parts[5]), http.StatusNotFound)
+			return
+		}
+		for _, version := range catalogVersions {
+			data = append(data, map[string]interface{}{
+				"version":                 version,
+				"supportedServiceClasses": catalogServiceClasses,
+				"releaseChannel":          "PRODUCTION",
+				"type":                    "eventBrokerServiceVersion",
+			})
+		}
+	default:
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	b, err := json.Marshal(map[string]interface{}{"data": data})
+	if err != nil {
+		log.Printf("fakeserver: failed to marshal result: %s\n", err)
+		return
+	}
+	w.Header().Add("Content-Type", "json")
+	_, err2 := w.Write(b)
+	if err2 != nil {
+		log.Printf("fakeserver: failed to write result: %s\n", err)
+	}
+}
+
 func (svr *Fakeserver) handleCreate(w http.ResponseWriter, body []byte) {
 	var jObj map[string]interface{}
 
@@ -187,6 +329,7 @@ func (svr *Fakeserver) handleCreate(w http.ResponseWriter, body []byte) {
 		State:                       "PENDING",
 		ServiceClassId:              jObj["serviceClassId"].(string),
 		DatacenterId:                jObj["datacenterId"].(string),
+		EnvironmentId:               orDefault(jObj["environmentId"], "test-env1"),
 		ClusterName:                 orDefault(jObj["clusterName"], "test-cluster1"),
 		MsgVpnName:                  orDefault(jObj["msgVpnName"], "test-vpn1"),
 		EventBrokerVersion:          orDefault(jObj["eventBrokerVersion"], "1.0.0"),
@@ -233,15 +376,36 @@ func (svr *Fakeserver) handleCreate(w http.ResponseWriter, body []byte) {
 	}
 }
 
-func (svr *Fakeserver) handleGet(w http.ResponseWriter, sInfo *ServiceInfo, id string) {
-	// complete creation after a certain delay, so we can test PENDING answers
+// progress completes the creation after a certain delay, so we can test PENDING answers.
+// The creation of brokers named failing-... fails.
+func (svr *Fakeserver) progress(sInfo *ServiceInfo, id string) {
 	if sInfo.State == "PENDING" {
-		if time.Since(sInfo.Created).Seconds() > 5.0 {
+		if time.Since(sInfo.Created).Seconds() > 5.0 && time.Since(sInfo.Updated).Seconds() > 1.0 {
 			sInfo.State = "COMPLETED"
+			if sInfo.Updated.IsZero() && strings.HasPrefix(sInfo.Name, "failing-") {
+				sInfo.State = "FAILED"
+			}
 		}
 		// writeback change
 		svr.objects[id] = *sInfo
 	}
+}
+
+// conflicting rejects PATCH and DELETE while an operation is ongoing, like missioncontrol does
+func (svr *Fakeserver) conflicting(w http.ResponseWriter, sInfo *ServiceInfo, id string) bool {
+	svr.progress(sInfo, id)
+	if sInfo.State != "PENDING" {
+		return false
+	}
+	if svr.debug {
+		log.Printf("fakeserver: Conflicting operation on service %s", id)
+	}
+	http.Error(w, fmt.Sprintf("{\"message\":\"Event broker service %s has an ongoing operation\",\"errorId\":\"409\"}", id), http.StatusConflict)
+	return true
+}
+
+func (svr *Fakeserver) handleGet(w http.ResponseWriter, sInfo *ServiceInfo, id string) {
+	svr.progress(sInfo, id)
 	if svr.debug {
 		log.Printf("fakeserver: GET service %v", sInfo)
 	}
@@ -253,8 +417,10 @@ func (svr *Fakeserver) handleGet(w http.ResponseWriter, sInfo *ServiceInfo, id s
 			"name":                      sInfo.Name,
 			"serviceClassId":            sInfo.ServiceClassId,
 			"datacenterId":              sInfo.DatacenterId,
+			"environmentId":             sInfo.EnvironmentId,
 			"createdTime":               sInfo.Created.Format(time.RFC3339),
 			"creationState":             sInfo.State,
+			"ongoingOperationIds":       ongoingOperationIds(sInfo),
 			"eventBrokerServiceVersion": sInfo.EventBrokerVersion,
 			"broker": map[string]interface{}{
 				"cluster": map[string]interface{}{
@@ -304,6 +470,68 @@ func (svr *Fakeserver) handleGet(w http.ResponseWriter, sInfo *ServiceInfo, id s
 	}
 }
 
+func (svr *Fakeserver) handleList(w http.ResponseWriter, r *http.Request) {
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 100
+	}
+	pageNumber, err := strconv.Atoi(r.URL.Query().Get("pageNumber"))
+	if err != nil || pageNumber < 1 {
+		pageNumber = 1
+	}
+
+	ids := make([]string, 0, len(svr.objects))
+	for id := range svr.objects {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	data := []interface{}{}
+	for i := (pageNumber - 1) * pageSize; i < len(ids) && i < pageNumber*pageSize; i++ {
+		sInfo := svr.objects[ids[i]]
+		svr.progress(&sInfo, ids[i])
+		data = append(data, map[string]interface{}{
+			"id":                  sInfo.ID,
+			"name":                sInfo.Name,
+			"serviceClassId":      sInfo.ServiceClassId,
+			"datacenterId":        sInfo.DatacenterId,
+			"environmentId":       sInfo.EnvironmentId,
+			"createdTime":         sInfo.Created.Format(time.RFC3339),
+			"creationState":       sInfo.State,
+			"ongoingOperationIds": ongoingOperationIds(&sInfo),
+		})
+	}
+	totalPages := (len(ids) + pageSize - 1) / pageSize
+	pagination := map[string]interface{}{
+		"pageNumber": pageNumber,
+		"count":      len(ids),
+		"pageSize":   pageSize,
+		"totalPages": totalPages,
+	}
+	if pageNumber < totalPages {
+		pagination["nextPage"] = pageNumber + 1
+	}
+	result := map[string]interface{}{
+		"data": data,
+		"meta": map[string]interface{}{
+			"pagination": pagination,
+		},
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("fakeserver: failed to marshal result: %s\n", err)
+		return
+	}
+	if svr.debug {
+		log.Printf("fakeserver: BODY %s", string(b))
+	}
+	w.Header().Add("Content-Type", "json")
+	_, err2 := w.Write(b)
+	if err2 != nil {
+		log.Printf("fakeserver: failed to write result: %s\n", err)
+	}
+}
+
 func (svr *Fakeserver) handlePatch(w http.ResponseWriter, sInfo *ServiceInfo, id string, body []byte) {
 	var jObj map[string]interface{}
 
@@ -340,6 +568,7 @@ func (svr *Fakeserver) handlePatch(w http.ResponseWriter, sInfo *ServiceInfo, id
 			"createdTime":               sInfo.Created.Format(time.RFC3339),
 			"updatedTime":               sInfo.Updated.Format(time.RFC3339),
 			"creationState":             sInfo.State,
+			"ongoingOperationIds":       ongoingOperationIds(sInfo),
 			"eventBrokerServiceVersion": sInfo.EventBrokerVersion,
 			"broker": map[string]interface{}{
 				"cluster": map[string]interface{}{
@@ -394,10 +623,11 @@ func (svr *Fakeserver) handleDelete(w http.ResponseWriter, sInfo *ServiceInfo, i
 	}
 	// handle delete
 	delete(svr.objects, id)
+	svr.deletions[id] = time.Now()
 	// return status DELETING
 	result := map[string]interface{}{
 		"data": map[string]interface{}{
-			"id":          "O" + sInfo.ID,
+			"id":          "D" + sInfo.ID,
 			"resourceId":  sInfo.ID,
 			"name":        sInfo.Name,
 			"createdTime": sInfo.Created.Format(time.RFC3339),
@@ -423,6 +653,65 @@ func (svr *Fakeserver) handleDelete(w http.ResponseWriter, sInfo *ServiceInfo, i
 	}
 }
 
+// handleOperation reports the create (O<id>) and update (U<id>) operations of a service, their status follows
+// the creation state, and the delete operation (D<id>), which succeeds 2s after the deletion
+func (svr *Fakeserver) handleOperation(w http.ResponseWriter, id string, operationId string) {
+	operation := map[string]interface{}{
+		"id":           operationId,
+		"resourceId":   id,
+		"resourceType": "service",
+		"type":         "operation",
+	}
+	sInfo, exists := svr.objects[id]
+	deleted, wasDeleted := svr.deletions[id]
+	switch {
+	case exists && (operationId == "O"+id || (operationId == "U"+id && !sInfo.Updated.IsZero())):
+		svr.progress(&sInfo, id)
+		operation["operationType"] = "createService"
+		operation["createdTime"] = sInfo.Created.Format(time.RFC3339)
+		if operationId == "U"+id {
+			operation["operationType"] = "serviceRequest"
+			operation["createdTime"] = sInfo.Updated.Format(time.RFC3339)
+		}
+		switch sInfo.State {
+		case "PENDING":
+			operation["status"] = "INPROGRESS"
+		case "FAILED":
+			operation["status"] = "FAILED"
+			operation["error"] = map[string]interface{}{
+				"message": "Provisioning of the event broker service failed",
+				"errorId": "fake-" + id,
+			}
+		default:
+			operation["status"] = "SUCCEEDED"
+		}
+	case wasDeleted && operationId == "D"+id:
+		operation["operationType"] = "deleteService"
+		operation["createdTime"] = deleted.Format(time.RFC3339)
+		operation["status"] = "INPROGRESS"
+		if time.Since(deleted) > 2*time.Second {
+			operation["status"] = "SUCCEEDED"
+		}
+	default:
+		log.Printf("fakeserver: Operation %s of service %s not found", operationId, id)
+		http.Error(w, fmt.Sprintf("{\"message\":\"Could not find operation with id %s\",\"errorId\":\"44\"}", operationId), http.StatusNotFound)
+		return
+	}
+	b, err := json.Marshal(map[string]interface{}{"data": operation})
+	if err != nil {
+		log.Printf("fakeserver: failed to marshal result: %s\n", err)
+		return
+	}
+	if svr.debug {
+		log.Printf("fakeserver: BODY %s", string(b))
+	}
+	w.Header().Add("Content-Type", "json")
+	_, err2 := w.Write(b)
+	if err2 != nil {
+		log.Printf("fakeserver: failed to write result: %s\n", err)
+	}
+}
+
 func (svr *Fakeserver) handleBrokerServices(w http.ResponseWriter, r *http.Request) {
 
 	var sInfo ServiceInfo
@@ -435,10 +724,21 @@ func (svr *Fakeserver) handleBrokerServices(w http.ResponseWriter, r *http.Reque
 	if err != nil {
 		return
 	}
+	if !svr.authorized(w, r) {
+		return
+	}
+	svr.mu.Lock()
+	defer svr.mu.Unlock()
 
 	if (len(parts) == 5 || (len(parts) == 6 && parts[5] == "")) && r.Method == "POST" {
 		svr.handleCreate(w, body)
 		return
+	} else if (len(parts) == 5 || (len(parts) == 6 && parts[5] == "")) && r.Method == "GET" {
+		svr.handleList(w, r)
+		return
+	} else if len(parts) == 8 && parts[6] == "operations" && r.Method == "GET" {
+		svr.handleOperation(w, parts[5], parts[7])
+		return
 	} else if len(parts) == 6 {
 		// an obj was specified.
 		id = parts[5]
@@ -456,10 +756,14 @@ func (svr *Fakeserver) handleBrokerServices(w http.ResponseWriter, r *http.Reque
 			svr.handleGet(w, &sInfo, id)
 			return
 		case "PATCH":
-			svr.handlePatch(w, &sInfo, id, body)
+			if !svr.conflicting(w, &sInfo, id) {
+				svr.handlePatch(w, &sInfo, id, body)
+			}
 			return
 		case "DELETE":
-			svr.handleDelete(w, &sInfo, id)
+			if !svr.conflicting(w, &sInfo, id) {
+				svr.handleDelete(w, &sInfo, id)
+			}
 			return
 		default:
 			log.Printf("fakeserver: unexpected method: %s\n", r.Method)
@@ -474,6 +778,17 @@ func (svr *Fakeserver) handleBrokerServices(w http.ResponseWriter, r *http.Reque
 
 }
 
+// ongoingOperationIds returns the id of the create (O<id>) or update (U<id>) operation while it is ongoing
+func ongoingOperationIds(sInfo *ServiceInfo) []string {
+	if sInfo.State != "PENDING" {
+		return []string{}
+	}
+	if !sInfo.Updated.IsZero() {
+		return []string{"U" + sInfo.ID}
+	}
+	return []string{"O" + sInfo.ID}
+}
+
 func orDefault(s interface{}, ds string) string {
 	if s != nil && s.(string) != "" {
 		return s.(string)
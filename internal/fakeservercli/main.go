@@ -16,15 +16,19 @@ func main() {
 	port := flag.Int("port", 8091, "The port fakeserver will listen on")
 	debug := flag.Bool("debug", false, "Enable debug output of the server")
 	baseSid := flag.Int("base-sid", 0, "generate SIDs from seqeunce starting with this. 0 = UUID-Generation instead")
+	tlsCert := flag.String("tls-cert", "", "PEM certificate file, serve https instead of http")
+	tlsKey := flag.String("tls-key", "", "PEM key file for -tls-cert")
 
 	flag.Parse()
 
 	svr := fakeserver.NewFakeServer(*port, apiServerObjects, false, *debug, *baseSid)
+	if *tlsCert != "" {
+		svr.SetTLS(*tlsCert, *tlsKey)
+	}
 
 	fmt.Printf("Starting server on port %d...\n", *port)
 
-	internalServer := svr.GetServer()
-	err := internalServer.ListenAndServe()
+	err := svr.ListenAndServe()
 	if nil != err {
 		fmt.Printf("Error with the internal TCP server: %s", err)
 		os.Exit(1)
@@ -0,0 +1,24 @@
+package missioncontrol
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrReadOnly is returned for mutating requests of a read-only client.
+var ErrReadOnly = errors.New("the provider is configured read_only, mutating MissionControl API calls are not allowed")
+
+// ReadOnlyDoer rejects every request that is not a GET, HEAD or OPTIONS before it is sent.
+// Use it with WithHTTPClient, so all generated operations - including future ones - are covered.
+type ReadOnlyDoer struct {
+	Next HttpRequestDoer
+}
+
+func (d *ReadOnlyDoer) Do(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return d.Next.Do(req)
+	}
+	return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, ErrReadOnly)
+}
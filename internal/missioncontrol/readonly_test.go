@@ -0,0 +1,40 @@
+package missioncontrol
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingDoer answers every request with 200
+type countingDoer struct {
+	calls int
+}
+
+func (d *countingDoer) Do(req *http.Request) (*http.Response, error) {
+	d.calls++
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: http.Header{}, Request: req}, nil
+}
+
+func TestReadOnlyDoer(t *testing.T) {
+	next := &countingDoer{}
+	client, err := NewClientWithResponses("http://localhost:8091", WithHTTPClient(&ReadOnlyDoer{Next: next}))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = client.GetServiceWithResponse(ctx, "svc1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, next.calls)
+
+	name := "renamed"
+	_, err = client.CreateServiceWithResponse(ctx, CreateServiceJSONRequestBody{Name: name})
+	assert.True(t, errors.Is(err, ErrReadOnly))
+	_, err = client.UpdateServiceWithResponse(ctx, "svc1", UpdateServiceJSONRequestBody{Name: &name})
+	assert.True(t, errors.Is(err, ErrReadOnly))
+	_, err = client.DeleteServiceWithResponse(ctx, "svc1")
+	assert.ErrorContains(t, err, "DELETE /api/v2/missionControl/eventBrokerServices/svc1")
+	assert.Equal(t, 1, next.calls, "mutating requests must not be sent")
+}
@@ -3,8 +3,6 @@ package provider
 import (
 	"context"
 	"fmt"
-	"net/http"
-	"net/http/httputil"
 	"strings"
 	"terraform-provider-gsolaceclustermgr/internal/missioncontrol"
 	"time"
@@ -19,6 +17,7 @@ import (
 type brokerDataSourceModel struct {
 	ID                     types.String `tfsdk:"id"`
 	DataCenterId           types.String `tfsdk:"datacenter_id"`
+	EnvironmentId          types.String `tfsdk:"environment_id"`
 	Name                   types.String `tfsdk:"name"`
 	ClusterName            types.String `tfsdk:"cluster_name"`
 	MsgVpnName             types.String `tfsdk:"msg_vpn_name"`
@@ -41,18 +40,6 @@ var (
 	_ datasource.DataSourceWithConfigure = &brokerDataSource{}
 )
 
-// helper func to add bearer token auth header to requests
-func (d *brokerDataSource) BearerReqEditorFn(ctx context.Context, req *http.Request) error {
-	req.Header.Set("Authorization", "Bearer "+d.cMProviderData.BearerToken)
-	dump, err := httputil.DumpRequestOut(req, true)
-	if err != nil {
-		tflog.Error(ctx, err.Error())
-	} else {
-		tflog.Debug(ctx, fmt.Sprintf("Request: %s", dump))
-	}
-	return nil
-}
-
 // NewCoffeesDataSource is a helper function to simplify the provider implementation.
 func NewBrokerDataSource() datasource.DataSource {
 	return &brokerDataSource{}
@@ -84,6 +71,9 @@ func (d *brokerDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 			"datacenter_id": schema.StringAttribute{
 				Computed: true,
 			},
+			"environment_id": schema.StringAttribute{
+				Computed: true,
+			},
 			// optional attributes that be filled with defaults from API server
 			"msg_vpn_name": schema.StringAttribute{
 				Computed: true,
@@ -134,6 +124,11 @@ func (d *brokerDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 // Read resource information.
 // resource.ReadRequest
 func (d *brokerDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	defer d.cMProviderData.addDeprecationWarnings(&resp.Diagnostics)
+	if deferralRequired(d.cMProviderData, req.ClientCapabilities.DeferralAllowed) {
+		resp.Deferred = &datasource.Deferred{Reason: datasource.DeferredReasonProviderConfigUnknown}
+		return
+	}
 
 	var currentState brokerDataSourceModel
 
@@ -154,7 +149,7 @@ func (d *brokerDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	}
 
 	// Get broker info
-	getResp, err := d.cMProviderData.Client.GetServiceWithResponse(ctx, queryID.ValueString(), &getParams, d.BearerReqEditorFn)
+	getResp, err := d.cMProviderData.Client.GetServiceWithResponse(ctx, queryID.ValueString(), &getParams)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error getting broker service info",
@@ -162,7 +157,6 @@ func (d *brokerDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		)
 		return
 	}
-	tflog.Debug(ctx, fmt.Sprintf("Response Body:%s", getResp.Body))
 	if getResp.StatusCode() == 401 {
 		var errMsg string
 		if getResp.JSON401 == nil {
@@ -205,10 +199,10 @@ func (d *brokerDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	}
 
 	// map to response state
-	tflog.Debug(ctx, fmt.Sprintf("Response Body:%s", getResp.Body))
 	currentState.ID = types.StringPointerValue(getResp.JSON200.Data.Id)
 	currentState.ServiceClassId = types.StringPointerValue((*string)(getResp.JSON200.Data.ServiceClassId))
 	currentState.DataCenterId = types.StringPointerValue(getResp.JSON200.Data.DatacenterId)
+	currentState.EnvironmentId = types.StringPointerValue(getResp.JSON200.Data.EnvironmentId)
 	currentState.EventBrokerVersion = types.StringValue(getResp.JSON200.Data.EventBrokerServiceVersion)
 	if getResp.JSON200.Data.CreatedTime != nil {
 		currentState.Created = types.StringValue(getResp.JSON200.Data.CreatedTime.Format(time.RFC850))
@@ -2,20 +2,21 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"net/http"
-	"net/http/httputil"
 	"regexp"
 	"strings"
 	"terraform-provider-gsolaceclustermgr/internal/missioncontrol"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -26,22 +27,25 @@ import (
 
 // brokerResourceModel maps the resource schema data.
 type brokerResourceModel struct {
-	ID                     types.String `tfsdk:"id"`
-	DataCenterId           types.String `tfsdk:"datacenter_id"`
-	Name                   types.String `tfsdk:"name"`
-	ClusterName            types.String `tfsdk:"cluster_name"`
-	MsgVpnName             types.String `tfsdk:"msg_vpn_name"`
-	Created                types.String `tfsdk:"created"`
-	LastUpdated            types.String `tfsdk:"last_updated"`
-	Status                 types.String `tfsdk:"status"`
-	ServiceClassId         types.String `tfsdk:"serviceclass_id"`
-	CustomRouterName       types.String `tfsdk:"custom_router_name"`
-	EventBrokerVersion     types.String `tfsdk:"event_broker_version"`
-	MaxSpoolUsage          types.Int32  `tfsdk:"max_spool_usage"`
-	MissionControlUserName types.String `tfsdk:"missioncontrol_username"`
-	MissionControlPassword types.String `tfsdk:"missioncontrol_password"`
-	HostNames              types.List   `tfsdk:"hostnames"`
-	ServiceEndpointId      types.String `tfsdk:"service_endpoint_id"`
+	ID                     types.String   `tfsdk:"id"`
+	DataCenterId           types.String   `tfsdk:"datacenter_id"`
+	EnvironmentId          types.String   `tfsdk:"environment_id"`
+	Name                   types.String   `tfsdk:"name"`
+	ClusterName            types.String   `tfsdk:"cluster_name"`
+	MsgVpnName             types.String   `tfsdk:"msg_vpn_name"`
+	Created                types.String   `tfsdk:"created"`
+	LastUpdated            types.String   `tfsdk:"last_updated"`
+	Status                 types.String   `tfsdk:"status"`
+	ServiceClassId         types.String   `tfsdk:"serviceclass_id"`
+	CustomRouterName       types.String   `tfsdk:"custom_router_name"`
+	EventBrokerVersion     types.String   `tfsdk:"event_broker_version"`
+	MaxSpoolUsage          types.Int32    `tfsdk:"max_spool_usage"`
+	MissionControlUserName types.String   `tfsdk:"missioncontrol_username"`
+	MissionControlPassword types.String   `tfsdk:"missioncontrol_password"`
+	HostNames              types.List     `tfsdk:"hostnames"`
+	ServiceEndpointId      types.String   `tfsdk:"service_endpoint_id"`
+	WaitForDeletion        types.Bool     `tfsdk:"wait_for_deletion"`
+	Timeouts               timeouts.Value `tfsdk:"timeouts"`
 }
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -49,25 +53,17 @@ var (
 	_ resource.Resource                = &brokerResource{}
 	_ resource.ResourceWithConfigure   = &brokerResource{}
 	_ resource.ResourceWithImportState = &brokerResource{}
+	_ resource.ResourceWithModifyPlan  = &brokerResource{}
 )
 
+// how long an unfinished broker is read after its creation failed or timed out
+const unfinishedBrokerReadTimeout = 30 * time.Second
+
 // NewBrokerResource is a helper function to simplify the provider implementation.
 func NewBrokerResource() resource.Resource {
 	return &brokerResource{}
 }
 
-// helper func to add bearer token auth header to requests
-func (r *brokerResource) BearerReqEditorFn(ctx context.Context, req *http.Request) error {
-	req.Header.Set("Authorization", "Bearer "+r.cMProviderData.BearerToken)
-	dump, err := httputil.DumpRequestOut(req, true)
-	if err != nil {
-		tflog.Error(ctx, err.Error())
-	} else {
-		tflog.Debug(ctx, fmt.Sprintf("Request: %s", dump))
-	}
-	return nil
-}
-
 // brokerResource is the resource implementation.
 type brokerResource struct {
 	cMProviderData CMProviderData
@@ -103,25 +99,36 @@ func (r *brokerResource) Configure(ctx context.Context, req resource.ConfigureRe
 func (r *brokerResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	tflog.Info(ctx, "define broker schema")
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Event Broker Resource. Note that *name* is the only attribute you can update without forcing a replacement",
+		MarkdownDescription: "Event Broker Resource. Note that *name* and *wait_for_deletion* are the only attributes you can update without forcing a replacement",
 		Attributes: map[string]schema.Attribute{
 			// creation params
 			"name": schema.StringAttribute{
 				MarkdownDescription: "Broker name",
 				Required:            true,
 			},
+			// required unless the provider defines a default
 			"serviceclass_id": schema.StringAttribute{
-				MarkdownDescription: "Serviceclass_id like DEVELOPER, ENTERPRISE_250_STANDALONE,... (see api docs)",
-				Required:            true,
+				MarkdownDescription: "Serviceclass_id like DEVELOPER, ENTERPRISE_250_STANDALONE,... (see api docs). Defaults to the provider's *default_serviceclass_id*",
+				Computed:            true,
+				Optional:            true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
 			"datacenter_id": schema.StringAttribute{
-				MarkdownDescription: "the datacenter, e.g. aks-germanywestcentral-1",
-				Required:            true,
+				MarkdownDescription: "the datacenter, e.g. aks-germanywestcentral-1. Defaults to the provider's *default_datacenter_id*",
+				Computed:            true,
+				Optional:            true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"environment_id": schema.StringAttribute{
+				MarkdownDescription: "the environment, defaults to the provider's *default_environment_id* or the default environment of the organization",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
 			// optional attributes that be filled with defaults from API server
@@ -173,6 +180,13 @@ func (r *brokerResource) Schema(ctx context.Context, _ resource.SchemaRequest, r
 					int32validator.Between(10, 6000),
 				},
 			},
+			// provider behaviour, not sent to the API
+			"wait_for_deletion": schema.BoolAttribute{
+				MarkdownDescription: "Wait until the broker is deleted on destroy, so a new broker with the same name or router name can be created. Defaults to true",
+				Computed:            true,
+				Optional:            true,
+				Default:             booldefault.StaticBool(true),
+			},
 			//
 			// computed attributes
 			"id": schema.StringAttribute{
@@ -209,11 +223,21 @@ func (r *brokerResource) Schema(ctx context.Context, _ resource.SchemaRequest, r
 				Sensitive: true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			// each defaults to the provider's polling_timeout_duration
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
 // Create a new resource.
 func (r *brokerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	defer r.cMProviderData.addDeprecationWarnings(&resp.Diagnostics)
 	tflog.Info(ctx, "Retrieving planned state")
 	// Retrieve values from plannedState
 	var plannedState brokerResourceModel
@@ -223,11 +247,21 @@ func (r *brokerResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	// the timeout covers waiting for a provisioning slot, the API calls and the operation
+	createTimeout, diags := plannedState.Timeouts.Create(ctx, r.cMProviderData.PollingTimeoutDuration)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	// Generate API request body from plan
 	var body = missioncontrol.CreateServiceJSONRequestBody{
 		Name:               plannedState.Name.ValueString(),
 		ServiceClassId:     missioncontrol.ServiceClassId(plannedState.ServiceClassId.ValueString()),
 		DatacenterId:       plannedState.DataCenterId.ValueString(),
+		EnvironmentId:      nullIfEmptyStringPtr(plannedState.EnvironmentId),
 		MsgVpnName:         nullIfEmptyStringPtr(plannedState.MsgVpnName),
 		ClusterName:        nullIfEmptyStringPtr(plannedState.ClusterName),
 		EventBrokerVersion: nullIfEmptyStringPtr(plannedState.EventBrokerVersion),
@@ -236,10 +270,21 @@ func (r *brokerResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 	tflog.Info(ctx, fmt.Sprintf("Request: %s %s %v %s using %s", "Foo", body.Name, body.ServiceClassId, body.DatacenterId, plannedState.ServiceClassId.ValueString()))
 
+	// the slot is held until the broker is provisioned
+	release, err := r.cMProviderData.ProvisioningSlots.Acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating broker service",
+			"Could not create broker service, "+err.Error(),
+		)
+		return
+	}
+	defer release()
+
 	// Use client to create new broker
 	tflog.Info(ctx, fmt.Sprintf("Creating broker service using %v", body))
 
-	createResp, err := r.cMProviderData.Client.CreateServiceWithResponse(ctx, body, r.BearerReqEditorFn)
+	createResp, err := r.cMProviderData.Client.CreateServiceWithResponse(ctx, body)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating broker service",
@@ -247,8 +292,6 @@ func (r *brokerResource) Create(ctx context.Context, req resource.CreateRequest,
 		)
 		return
 	}
-	tflog.Debug(ctx, fmt.Sprintf("Response Header:%s", createResp.HTTPResponse.Header))
-	tflog.Debug(ctx, fmt.Sprintf("Response Body:%s", createResp.Body))
 	// As of 20250324 the errorResponse is an application/xml error object, it will not be mapped to json"
 	if createResp.StatusCode() == 400 {
 		var errMsg string
@@ -319,36 +362,35 @@ func (r *brokerResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	resourceId := *(createResp.JSON202.Data.ResourceId)
+	operationId := *(createResp.JSON202.Data.Id)
 
-	tflog.Info(ctx, fmt.Sprintf("Waiting for broker service using %s to finish creation", resourceId))
-
-	// TODO: polling GET with full expansion is maybe expensive - we could poll for the operation and fetch the full state once instead
-
-	timeout := time.Now().Add(r.cMProviderData.PollingTimeoutDuration)
-	for created := false; !created; {
-		// sleep, timeout
-		if time.Now().After(timeout) {
-			resp.Diagnostics.AddError(
-				"Timeout",
-				"timeout creating broker service",
-			)
-			return
-		}
-		time.Sleep(r.cMProviderData.PollingIntervalDuration)
-		tflog.Info(ctx, fmt.Sprintf("Checking broker status for %s", resourceId))
-
-		r.fullGet(ctx, resourceId, &plannedState, &resp.Diagnostics)
-
-		tflog.Info(ctx, fmt.Sprintf("Broker status %s", plannedState.Status.ValueString()))
-
-		if resp.Diagnostics.HasError() {
-			return
-		}
+	tflog.Info(ctx, fmt.Sprintf("Waiting for operation %s of broker service %s to finish creation", operationId, resourceId))
 
-		if plannedState.Status.ValueString() == string(missioncontrol.ServiceCreationStateCOMPLETED) {
-			created = true
+	// the status of all creating brokers is polled with one shared scan, the operation is only read
+	// for the error details of a failed creation and the expanded service is fetched once at the end
+	state, err := r.cMProviderData.StatusPoller.WaitForCreation(ctx, resourceId)
+	if err == nil && state == missioncontrol.ServiceCreationStateFAILED {
+		err = r.cMProviderData.operationError(ctx, resourceId, operationId)
+	}
+	if err != nil {
+		// the service exists, also when it failed or is still provisioning
+		var failedErr *operationFailedError
+		if errors.As(err, &failedErr) {
+			plannedState.Status = types.StringValue(string(missioncontrol.ServiceCreationStateFAILED))
 		}
+		r.saveUnfinishedBroker(ctx, resourceId, &plannedState, resp)
+		resp.Diagnostics.AddError(
+			waitErrorSummary(err, "Error creating broker service"),
+			"Could not create broker service: "+err.Error()+
+				fmt.Sprintf("\n\nThe broker service %s has been saved as tainted, it will be replaced on the next apply.", resourceId),
+		)
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("Broker status %s", state))
 
+	r.fullGet(ctx, resourceId, &plannedState, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	// Set state to fully populated data
@@ -359,9 +401,47 @@ func (r *brokerResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 }
 
+// saveUnfinishedBroker keeps a broker whose creation did not succeed in state. Terraform taints it because Create
+// fails, and replaces it on the next apply instead of leaving an orphaned, billed service behind.
+func (r *brokerResource) saveUnfinishedBroker(ctx context.Context, id string, model *brokerResourceModel, resp *resource.CreateResponse) {
+	model.ID = types.StringValue(id)
+
+	// best effort, the context may be done already and a failed service may not be readable
+	getCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), unfinishedBrokerReadTimeout)
+	defer cancel()
+	var getDiags diag.Diagnostics
+	r.fullGet(getCtx, id, model, &getDiags)
+	if getDiags.HasError() {
+		tflog.Warn(ctx, fmt.Sprintf("Could not read unfinished broker service %s, saving its id only", id))
+	}
+
+	// the state must not contain unknown values
+	for _, v := range []*types.String{
+		&model.DataCenterId, &model.EnvironmentId, &model.ClusterName, &model.MsgVpnName, &model.Created,
+		&model.LastUpdated, &model.Status, &model.ServiceClassId, &model.CustomRouterName, &model.EventBrokerVersion,
+		&model.MissionControlUserName, &model.MissionControlPassword, &model.ServiceEndpointId,
+	} {
+		if v.IsUnknown() {
+			*v = types.StringNull()
+		}
+	}
+	if model.MaxSpoolUsage.IsUnknown() {
+		model.MaxSpoolUsage = types.Int32Null()
+	}
+	if model.HostNames.IsUnknown() {
+		model.HostNames = types.ListNull(types.StringType)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
 // Read resource information.
 func (r *brokerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	defer r.cMProviderData.addDeprecationWarnings(&resp.Diagnostics)
 	tflog.Info(ctx, "retrieve current state")
+	if deferralRequired(r.cMProviderData, req.ClientCapabilities.DeferralAllowed) {
+		resp.Deferred = &resource.Deferred{Reason: resource.DeferredReasonProviderConfigUnknown}
+		return
+	}
 	// Get current currentState
 	var currentState brokerResourceModel
 
@@ -371,6 +451,19 @@ func (r *brokerResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
+	readTimeout, diags := currentState.Timeouts.Read(ctx, r.cMProviderData.PollingTimeoutDuration)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	// imported resources have no value yet
+	if currentState.WaitForDeletion.IsNull() {
+		currentState.WaitForDeletion = types.BoolValue(true)
+	}
+
 	// Get refreshed broker state
 	r.fullGet(ctx, currentState.ID.ValueString(), &currentState, &resp.Diagnostics)
 	if resp.Diagnostics.WarningsCount() > 0 {
@@ -394,6 +487,7 @@ func (r *brokerResource) Read(ctx context.Context, req resource.ReadRequest, res
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *brokerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	defer r.cMProviderData.addDeprecationWarnings(&resp.Diagnostics)
 	tflog.Info(ctx, "Retrieving planned state")
 	// Retrieve values from plannedState
 	var plannedState brokerResourceModel
@@ -403,34 +497,72 @@ func (r *brokerResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	// Generate API request body from plan
-	var body = missioncontrol.UpdateServiceJSONRequestBody{
-		Name: plannedState.Name.ValueStringPointer(),
+	var priorState brokerResourceModel
+	diags = req.State.Get(ctx, &priorState)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	brokerId := plannedState.ID.ValueString()
-
-	// Use client to update broker
-	tflog.Info(ctx, fmt.Sprintf("Updating broker service using %v", body))
 
-	updateResp, err := r.cMProviderData.Client.UpdateServiceWithResponse(ctx, brokerId, body, r.BearerReqEditorFn)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating broker service",
-			"Could not update broker service, unexpected error: "+err.Error(),
-		)
+	// the timeout covers waiting for conflicting operations, the API calls and the operation
+	updateTimeout, diags := plannedState.Timeouts.Update(ctx, r.cMProviderData.PollingTimeoutDuration)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+	brokerId := plannedState.ID.ValueString()
 
-	// NOTE: in theory we will get a PENDING or INPROGRESS status, and should wait for the operatin to finish.
-	// It is only a quick renaming however, so we do not bother...
-	tflog.Debug(ctx, fmt.Sprintf("Response Body:%s", updateResp.Body))
-	if updateResp.StatusCode() != 200 {
-		// do not catch 404 (vanished resources), that is an error
-		resp.Diagnostics.AddError(
-			"Error creating broker service",
-			fmt.Sprintf("Unexpected response code: %v", updateResp.StatusCode()),
-		)
-		return
+	// wait_for_deletion alone is not sent to the API
+	if !plannedState.Name.Equal(priorState.Name) {
+		// Generate API request body from plan
+		var body = missioncontrol.UpdateServiceJSONRequestBody{
+			Name: plannedState.Name.ValueStringPointer(),
+		}
+
+		// Use client to update broker
+		tflog.Info(ctx, fmt.Sprintf("Updating broker service using %v", body))
+
+		var updateResp *missioncontrol.UpdateServiceResponse
+		err := r.cMProviderData.mutateService(ctx, brokerId, func() (int, []byte, error) {
+			var err error
+			updateResp, err = r.cMProviderData.Client.UpdateServiceWithResponse(ctx, brokerId, body)
+			if err != nil {
+				return 0, nil, err
+			}
+			return updateResp.StatusCode(), updateResp.Body, nil
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating broker service",
+				"Could not update broker service, unexpected error: "+err.Error(),
+			)
+			return
+		}
+
+		if updateResp.StatusCode() != 200 || updateResp.JSON200 == nil {
+			// do not catch 404 (vanished resources), that is an error
+			resp.Diagnostics.AddError(
+				"Error updating broker service",
+				fmt.Sprintf("Unexpected response code: %v", updateResp.StatusCode()),
+			)
+			return
+		}
+
+		// the update runs as an operation of the service, the latest ongoing operation is ours
+		ongoing := updateResp.JSON200.Data.OngoingOperationIds
+		if ongoing != nil && len(*ongoing) > 0 {
+			operationId := (*ongoing)[len(*ongoing)-1]
+			tflog.Info(ctx, fmt.Sprintf("Waiting for operation %s of broker service %s to finish the update", operationId, brokerId))
+			if _, err := r.cMProviderData.waitForOperation(ctx, brokerId, operationId, "updating broker "+brokerId); err != nil {
+				resp.Diagnostics.AddError(
+					waitErrorSummary(err, "Error updating broker service"),
+					"Could not update broker service: "+err.Error(),
+				)
+				return
+			}
+		}
 	}
 
 	// Update will NOT deliver expanded infos (epand query param is not specified for this method)
@@ -454,6 +586,7 @@ func (r *brokerResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *brokerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	defer r.cMProviderData.addDeprecationWarnings(&resp.Diagnostics)
 	// Retrieve values from state
 	var currentState brokerResourceModel
 	diags := req.State.Get(ctx, &currentState)
@@ -462,9 +595,36 @@ func (r *brokerResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	// the timeout covers waiting for a provisioning slot and conflicting operations, the API call and the operation
+	deleteTimeout, diags := currentState.Timeouts.Delete(ctx, r.cMProviderData.PollingTimeoutDuration)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	release, err := r.cMProviderData.ProvisioningSlots.Acquire(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting broker service",
+			"Could not delete broker service, "+err.Error(),
+		)
+		return
+	}
+	defer release()
+
 	// then delete
 	brokerId := currentState.ID.ValueString()
-	delResp, err := r.cMProviderData.Client.DeleteServiceWithResponse(ctx, brokerId, r.BearerReqEditorFn)
+	var delResp *missioncontrol.DeleteServiceResponse
+	err = r.cMProviderData.mutateService(ctx, brokerId, func() (int, []byte, error) {
+		var err error
+		delResp, err = r.cMProviderData.Client.DeleteServiceWithResponse(ctx, brokerId)
+		if err != nil {
+			return 0, nil, err
+		}
+		return delResp.StatusCode(), delResp.Body, nil
+	})
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error getting broker service info",
@@ -472,7 +632,6 @@ func (r *brokerResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		)
 		return
 	}
-	tflog.Debug(ctx, fmt.Sprintf("Response Body:%s", delResp.Body))
 	if delResp.StatusCode() != 202 {
 
 		// handling a vanished resource (likely already detected in plan/read)
@@ -488,13 +647,178 @@ func (r *brokerResource) Delete(ctx context.Context, req resource.DeleteRequest,
 			"Error Checking broker status",
 			fmt.Sprintf("Unexpected response code: %v", delResp.StatusCode()),
 		)
-		tflog.Debug(ctx, fmt.Sprintf("Response Body:%s", delResp.Body))
 		return
 	}
 	operationId := *(delResp.JSON202.Data.Id)
 	tflog.Debug(ctx, fmt.Sprintf("Delete-Operation %s on broker %s has been started.", operationId, brokerId))
 
-	// TODO we might want to wait for the operation to finish?
+	if !currentState.WaitForDeletion.IsNull() && !currentState.WaitForDeletion.ValueBool() {
+		return
+	}
+	if err := r.cMProviderData.waitForDeletion(ctx, brokerId, operationId); err != nil {
+		resp.Diagnostics.AddError(
+			waitErrorSummary(err, "Error deleting broker service"),
+			"Could not delete broker service: "+err.Error(),
+		)
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("Broker %s has been deleted", brokerId))
+}
+
+// ModifyPlan fills datacenter, serviceclass and environment from the provider defaults when they are not configured
+// and validates new values against the catalog.
+func (r *brokerResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	defer r.cMProviderData.addDeprecationWarnings(&resp.Diagnostics)
+	// nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	// provider configuration unknown, plan again once it is known
+	if deferralRequired(r.cMProviderData, req.ClientCapabilities.DeferralAllowed) {
+		resp.Deferred = &resource.Deferred{Reason: resource.DeferredReasonProviderConfigUnknown}
+		return
+	}
+	// not configured, e.g. during validation
+	if r.cMProviderData.Client == nil {
+		return
+	}
+	r.planProviderDefault(ctx, req, resp, "datacenter_id", "default_datacenter_id", r.cMProviderData.DefaultDatacenterId, true)
+	r.planProviderDefault(ctx, req, resp, "serviceclass_id", "default_serviceclass_id", r.cMProviderData.DefaultServiceClassId, true)
+	r.planProviderDefault(ctx, req, resp, "environment_id", "default_environment_id", r.cMProviderData.DefaultEnvironmentId, false)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.validateCatalog(ctx, req, resp)
+}
+
+// validateCatalog checks new datacenter, serviceclass and broker version values against the catalog and warns
+// about unknown values, so typos show up in the plan and not after minutes of apply. The catalog may not list
+// everything the API accepts, so the apply is not blocked. The checks are skipped when the catalog cannot be read.
+func (r *brokerResource) validateCatalog(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	catalog := r.cMProviderData.CatalogCache
+
+	datacenterId, datacenterChanged := plannedChange(ctx, req, resp, "datacenter_id")
+	if datacenterChanged {
+		datacenters, err := catalog.Datacenters(ctx)
+		if err != nil {
+			tflog.Warn(ctx, "Skipping datacenter validation: "+err.Error())
+		} else if !containsDatacenter(datacenters, datacenterId) {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("datacenter_id"),
+				"Unknown datacenter_id",
+				fmt.Sprintf("Datacenter %s does not exist or is not visible with this token.", datacenterId),
+			)
+		}
+	}
+
+	if serviceClassId, changed := plannedChange(ctx, req, resp, "serviceclass_id"); changed {
+		serviceClasses, err := catalog.ServiceClasses(ctx)
+		if err != nil {
+			tflog.Warn(ctx, "Skipping serviceclass validation: "+err.Error())
+		} else if !containsServiceClass(serviceClasses, serviceClassId) {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("serviceclass_id"),
+				"Unknown serviceclass_id",
+				fmt.Sprintf("Service class %s does not exist.", serviceClassId),
+			)
+		}
+	}
+
+	// the versions depend on the datacenter, a new datacenter checks the version again
+	version, versionChanged := plannedChange(ctx, req, resp, "event_broker_version")
+	if datacenterId != "" && version != "" && (versionChanged || datacenterChanged) {
+		versions, err := catalog.EventBrokerServiceVersions(ctx, datacenterId)
+		if err != nil {
+			tflog.Warn(ctx, "Skipping event_broker_version validation: "+err.Error())
+		} else if !containsVersion(versions, version) {
+			available := make([]string, 0, len(versions))
+			for _, v := range versions {
+				available = append(available, v.Version)
+			}
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("event_broker_version"),
+				"Unknown event_broker_version",
+				fmt.Sprintf("Version %s is not available in datacenter %s, available versions: %s.", version, datacenterId, strings.Join(available, ", ")),
+			)
+		}
+	}
+}
+
+// helper returning the known planned value of an attribute and whether it differs from the state
+func plannedChange(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, attrName string) (string, bool) {
+	attr := path.Root(attrName)
+
+	var planValue types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, attr, &planValue)...)
+	if planValue.IsNull() || planValue.IsUnknown() {
+		return "", false
+	}
+	if req.State.Raw.IsNull() {
+		return planValue.ValueString(), true
+	}
+	var stateValue types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, attr, &stateValue)...)
+	return planValue.ValueString(), !stateValue.Equal(planValue)
+}
+
+func containsDatacenter(datacenters []missioncontrol.Datacenter, id string) bool {
+	for _, d := range datacenters {
+		if d.Id != nil && *d.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsServiceClass(serviceClasses []missioncontrol.ServiceClass, id string) bool {
+	for _, s := range serviceClasses {
+		if s.Id != nil && string(*s.Id) == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsVersion(versions []missioncontrol.EventBrokerServiceVersion, version string) bool {
+	for _, v := range versions {
+		if v.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// helper to resolve an unconfigured attribute from the provider default on creation. Existing brokers keep
+// the value they were created with, a changed default must not replace them.
+func (r *brokerResource) planProviderDefault(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse,
+	attrName string, providerAttrName string, providerDefault string, required bool) {
+	attr := path.Root(attrName)
+
+	var configValue types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, attr, &configValue)...)
+	if resp.Diagnostics.HasError() || !configValue.IsNull() {
+		// configured values (even unknown ones) win
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		// keep the value resolved on creation
+		var stateValue types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, attr, &stateValue)...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, attr, stateValue)...)
+		return
+	}
+
+	switch {
+	case providerDefault != "":
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, attr, types.StringValue(providerDefault))...)
+	case required:
+		resp.Diagnostics.AddAttributeError(
+			attr,
+			"Missing "+attrName,
+			fmt.Sprintf("Set %s on the resource or %s on the provider.", attrName, providerAttrName),
+		)
+	}
 }
 
 func (r *brokerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -513,7 +837,7 @@ func (r *brokerResource) fullGet(ctx context.Context, id string, model *brokerRe
 	}
 
 	// Get refreshed broker state
-	getResp, err := r.cMProviderData.Client.GetServiceWithResponse(ctx, id, &getParams, r.BearerReqEditorFn)
+	getResp, err := r.cMProviderData.Client.GetServiceWithResponse(ctx, id, &getParams)
 	if err != nil {
 		diagnostics.AddError(
 			"Error getting broker service",
@@ -521,7 +845,6 @@ func (r *brokerResource) fullGet(ctx context.Context, id string, model *brokerRe
 		)
 		return
 	}
-	tflog.Debug(ctx, fmt.Sprintf("Response Body:%s", getResp.Body))
 	if getResp.StatusCode() != 200 {
 
 		// handle vanished resources
@@ -571,7 +894,6 @@ func (r *brokerResource) fullGet(ctx context.Context, id string, model *brokerRe
 		return
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("Response Body:%s", getResp.Body))
 	// extract all infos when status is COMPLETED
 	if *(getResp.JSON200.Data.CreationState) == missioncontrol.ServiceCreationStateCOMPLETED {
 		model.ID = types.StringPointerValue(getResp.JSON200.Data.Id)
@@ -587,6 +909,7 @@ func (r *brokerResource) fullGet(ctx context.Context, id string, model *brokerRe
 		}
 		model.ServiceClassId = types.StringPointerValue((*string)(getResp.JSON200.Data.ServiceClassId))
 		model.DataCenterId = types.StringPointerValue(getResp.JSON200.Data.DatacenterId)
+		model.EnvironmentId = types.StringPointerValue(getResp.JSON200.Data.EnvironmentId)
 		model.EventBrokerVersion = types.StringValue(getResp.JSON200.Data.EventBrokerServiceVersion)
 		model.Status = types.StringValue(string(*(getResp.JSON200.Data.CreationState)))
 		model.Name = types.StringPointerValue(getResp.JSON200.Data.Name)
@@ -1,16 +1,27 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
+	"strings"
 	"terraform-provider-gsolaceclustermgr/internal/fakeserver"
+	"terraform-provider-gsolaceclustermgr/internal/missioncontrol"
 	"testing"
+	"time"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
 	"github.com/hashicorp/terraform-plugin-testing/statecheck"
 	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/stretchr/testify/assert"
 )
 
 var svr *fakeserver.Fakeserver
@@ -254,6 +265,287 @@ func TestAccBrokerResource(t *testing.T) {
 
 }
 
+func TestAccBrokerResourceProviderDefaults(t *testing.T) {
+	if os.Getenv("FAKE_SERVER_EXT") == "" {
+		startFakeServer()
+		defer stopFakeServer()
+	}
+	providerWithDefaults := `
+	provider "gsolaceclustermgr" {
+		bearer_token = "bt42"
+		host = "http://localhost:8091"
+		polling_interval_duration = "2s"
+		polling_timeout_duration = "1m"
+		default_datacenter_id = "aks-germanywestcentral"
+		default_serviceclass_id = "DEVELOPER"
+		default_environment_id = "test-env2"
+	}
+	`
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// missing attributes without provider defaults
+			{
+				Config: providerConfig + `
+				resource "gsolaceclustermgr_broker" "test4" {
+					name = "ocs-prov-test4"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Missing datacenter_id"),
+			},
+			{
+				Config: providerWithDefaults + `
+				resource "gsolaceclustermgr_broker" "test4" {
+					name = "ocs-prov-test4"
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"gsolaceclustermgr_broker.test4",
+						tfjsonpath.New("datacenter_id"),
+						knownvalue.StringExact("aks-germanywestcentral"),
+					),
+					statecheck.ExpectKnownValue(
+						"gsolaceclustermgr_broker.test4",
+						tfjsonpath.New("serviceclass_id"),
+						knownvalue.StringExact("DEVELOPER"),
+					),
+					statecheck.ExpectKnownValue(
+						"gsolaceclustermgr_broker.test4",
+						tfjsonpath.New("environment_id"),
+						knownvalue.StringExact("test-env2"),
+					),
+					statecheck.ExpectKnownValue(
+						"gsolaceclustermgr_broker.test4",
+						tfjsonpath.New("wait_for_deletion"),
+						knownvalue.Bool(true),
+					),
+				},
+			},
+			// changed defaults apply to new brokers only
+			{
+				Config: strings.Replace(providerWithDefaults, `"test-env2"`, `"test-env3"`, 1) + `
+				resource "gsolaceclustermgr_broker" "test4" {
+					name = "ocs-prov-test4"
+				}
+				`,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccBrokerResourceCatalogValidation(t *testing.T) {
+	if os.Getenv("FAKE_SERVER_EXT") == "" {
+		startFakeServer()
+		defer stopFakeServer()
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+				resource "gsolaceclustermgr_broker" "test5" {
+					name            = "ocs-catalog-test5"
+					serviceclass_id = "DEVELOPER"
+					datacenter_id   = "aks-nowhere"
+				}
+				`,
+				// unknown catalog values are warnings, the plan succeeds
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: providerConfig + `
+				resource "gsolaceclustermgr_broker" "test5" {
+					name            = "ocs-catalog-test5"
+					serviceclass_id = "TINY"
+					datacenter_id   = "aks-germanywestcentral"
+				}
+				`,
+				// unknown catalog values are warnings, the plan succeeds
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: providerConfig + `
+				resource "gsolaceclustermgr_broker" "test5" {
+					name                 = "ocs-catalog-test5"
+					serviceclass_id      = "DEVELOPER"
+					datacenter_id        = "aks-germanywestcentral"
+					event_broker_version = "0.0.1"
+				}
+				`,
+				// unknown catalog values are warnings, the plan succeeds
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestModifyPlanWarnsOnUnknownCatalogValues(t *testing.T) {
+	ctx := context.Background()
+	catalog := httptest.NewServer(fakeserver.NewFakeServer(0, map[string]fakeserver.ServiceInfo{}, false, false, 0).GetServer().Handler)
+	defer catalog.Close()
+	client, err := missioncontrol.NewClientWithResponses(catalog.URL, missioncontrol.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer test")
+		return nil
+	}))
+	assert.NoError(t, err)
+	r := &brokerResource{cMProviderData: CMProviderData{Client: client, CatalogCache: NewCatalogCache(client, time.Minute)}}
+
+	schemaResp := fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	schemaType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	for name, values := range map[string]map[string]string{
+		"datacenter_id":        {"datacenter_id": "aks-nowhere", "serviceclass_id": "DEVELOPER"},
+		"serviceclass_id":      {"datacenter_id": "aks-germanywestcentral", "serviceclass_id": "TINY"},
+		"event_broker_version": {"datacenter_id": "aks-germanywestcentral", "serviceclass_id": "DEVELOPER", "event_broker_version": "0.0.1"},
+		"":                     {"datacenter_id": "aks-germanywestcentral", "serviceclass_id": "DEVELOPER", "event_broker_version": "1.2.3"},
+	} {
+		values["name"] = "ocs-catalog-test"
+		plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: testBrokerValue(schemaType, values)}
+		req := fwresource.ModifyPlanRequest{
+			Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: plan.Raw},
+			Plan:   plan,
+			State:  tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaType, nil)},
+		}
+		resp := fwresource.ModifyPlanResponse{Plan: plan}
+		r.ModifyPlan(ctx, req, &resp)
+
+		assert.False(t, resp.Diagnostics.HasError(), name)
+		if name == "" {
+			assert.Empty(t, resp.Diagnostics.Warnings())
+		} else if assert.Equal(t, 1, resp.Diagnostics.WarningsCount(), name) {
+			assert.Equal(t, "Unknown "+name, resp.Diagnostics.Warnings()[0].Summary())
+		}
+	}
+}
+
+func TestModifyPlanKeepsStateOnChangedDefaults(t *testing.T) {
+	ctx := context.Background()
+	r := &brokerResource{cMProviderData: CMProviderData{
+		Client:                &missioncontrol.ClientWithResponses{},
+		DefaultDatacenterId:   "aks-newdefault",
+		DefaultServiceClassId: "ENTERPRISE_250_STANDALONE",
+		DefaultEnvironmentId:  "test-env3",
+	}}
+	schemaResp := fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	schemaType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	// the broker was created with other defaults, the configuration does not set the attributes
+	config := testBrokerValue(schemaType, map[string]string{"name": "ocs-defaults-test"})
+	state := testBrokerValue(schemaType, map[string]string{
+		"id":              "svc1",
+		"name":            "ocs-defaults-test",
+		"datacenter_id":   "aks-germanywestcentral",
+		"serviceclass_id": "DEVELOPER",
+		"environment_id":  "test-env1",
+	})
+	req := fwresource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config},
+		Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: state},
+		State:  tfsdk.State{Schema: schemaResp.Schema, Raw: state},
+	}
+	resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+	r.planProviderDefault(ctx, req, &resp, "datacenter_id", "default_datacenter_id", r.cMProviderData.DefaultDatacenterId, true)
+	r.planProviderDefault(ctx, req, &resp, "serviceclass_id", "default_serviceclass_id", r.cMProviderData.DefaultServiceClassId, true)
+	r.planProviderDefault(ctx, req, &resp, "environment_id", "default_environment_id", r.cMProviderData.DefaultEnvironmentId, false)
+
+	assert.False(t, resp.Diagnostics.HasError())
+	assert.Empty(t, resp.RequiresReplace, "a changed default does not replace existing brokers")
+	var planned brokerResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &planned)...)
+	assert.Equal(t, "aks-germanywestcentral", planned.DataCenterId.ValueString())
+	assert.Equal(t, "DEVELOPER", planned.ServiceClassId.ValueString())
+	assert.Equal(t, "test-env1", planned.EnvironmentId.ValueString())
+}
+
+// testBrokerValue returns a broker object with the given string attributes, all others are null
+func testBrokerValue(schemaType tftypes.Object, values map[string]string) tftypes.Value {
+	attributes := map[string]tftypes.Value{}
+	for attrName, attrType := range schemaType.AttributeTypes {
+		attributes[attrName] = tftypes.NewValue(attrType, nil)
+	}
+	for attrName, value := range values {
+		attributes[attrName] = tftypes.NewValue(tftypes.String, value)
+	}
+	return tftypes.NewValue(schemaType, attributes)
+}
+
+func TestAccBrokerResourceTimeouts(t *testing.T) {
+	if os.Getenv("FAKE_SERVER_EXT") == "" {
+		startFakeServer()
+		defer stopFakeServer()
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+				resource "gsolaceclustermgr_broker" "test6" {
+					name            = "ocs-timeouts-test6"
+					serviceclass_id = "DEVELOPER"
+					datacenter_id   = "aks-germanywestcentral"
+					timeouts {
+						create = "invalid"
+					}
+				}
+				`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Invalid Attribute Value Time Duration"),
+			},
+			// the fakeserver needs 5s to create a broker
+			{
+				Config: providerConfig + `
+				resource "gsolaceclustermgr_broker" "test6" {
+					name            = "ocs-timeouts-test6"
+					serviceclass_id = "DEVELOPER"
+					datacenter_id   = "aks-germanywestcentral"
+					timeouts {
+						create = "3s"
+					}
+				}
+				`,
+				ExpectError: regexp.MustCompile(`timeout\s+after\s+3s\s+creating\s+broker`),
+			},
+		},
+	})
+}
+
+func TestAccBrokerResourceFailedCreation(t *testing.T) {
+	if os.Getenv("FAKE_SERVER_EXT") == "" {
+		startFakeServer()
+		defer stopFakeServer()
+	}
+	config := providerConfig + `
+	resource "gsolaceclustermgr_broker" "test7" {
+		name            = "failing-test7"
+		serviceclass_id = "DEVELOPER"
+		datacenter_id   = "aks-germanywestcentral"
+	}
+	`
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// the fakeserver fails the creation of brokers named failing-...
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile(`Provisioning\s+of\s+the\s+event\s+broker\s+service\s+failed\s+\(errorId\s+fake-`),
+			},
+			// the failed broker is in state and tainted, so it is replaced
+			{
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
 func TestAccBrokerDataSource(t *testing.T) {
 	if os.Getenv("EXT_SERVER") == "" {
 		startFakeServer()
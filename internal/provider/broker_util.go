@@ -42,3 +42,9 @@ func getRouterPrefix(routerName string) string {
 	re := regexp.MustCompile(`^(.*)(primary|backup|monitoring)+(cn)?`)
 	return re.ReplaceAllString(routerName, "$1")
 }
+
+// deferralRequired reports whether a resource or data source has to defer, because the provider
+// configuration was unknown and Configure did not create a client
+func deferralRequired(providerData CMProviderData, deferralAllowed bool) bool {
+	return deferralAllowed && providerData.Client == nil
+}
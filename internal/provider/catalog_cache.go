@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"terraform-provider-gsolaceclustermgr/internal/missioncontrol"
+	"time"
+)
+
+// how long catalog answers are reused, the catalog rarely changes during a terraform run
+const catalogCacheTTL = 10 * time.Minute
+
+// page size of the datacenter list, the maximum supported by the API
+const catalogPageSize = 100
+
+// CatalogCache memoises the read only catalog endpoints (datacenters, service classes and broker versions).
+// Concurrent lookups of the same key share one API call. Failed calls are cached as well, so an unreachable
+// catalog, e.g. in an offline plan, costs one call and its retries per run and not per broker.
+type CatalogCache struct {
+	client *missioncontrol.ClientWithResponses
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*catalogEntry
+}
+
+type catalogEntry struct {
+	done    chan struct{} // closed when the call has finished
+	value   interface{}
+	err     error
+	fetched time.Time
+}
+
+func NewCatalogCache(client *missioncontrol.ClientWithResponses, ttl time.Duration) *CatalogCache {
+	return &CatalogCache{
+		client:  client,
+		ttl:     ttl,
+		entries: map[string]*catalogEntry{},
+	}
+}
+
+// get returns the cached value of the key, or calls fetch once for all concurrent callers
+func (c *CatalogCache) get(ctx context.Context, key string, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok || entry.expired(c.ttl) {
+		entry = &catalogEntry{done: make(chan struct{})}
+		c.entries[key] = entry
+		// the call serves all waiters, it must not end with the request that started it
+		go c.fetch(context.WithoutCancel(ctx), key, entry, fetch)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-entry.done:
+		return entry.value, entry.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *CatalogCache) fetch(ctx context.Context, key string, entry *catalogEntry, fetch func(ctx context.Context) (interface{}, error)) {
+	value, err := fetch(ctx)
+
+	c.mu.Lock()
+	entry.value, entry.err, entry.fetched = value, err, time.Now()
+	c.mu.Unlock()
+	close(entry.done)
+}
+
+// expired reports finished entries older than the ttl, the caller holds the lock
+func (e *catalogEntry) expired(ttl time.Duration) bool {
+	select {
+	case <-e.done:
+		return time.Since(e.fetched) > ttl
+	default:
+		// still in flight
+		return false
+	}
+}
+
+// Datacenters returns all datacenters visible to the token.
+func (c *CatalogCache) Datacenters(ctx context.Context) ([]missioncontrol.Datacenter, error) {
+	value, err := c.get(ctx, "datacenters", func(ctx context.Context) (interface{}, error) {
+		var datacenters []missioncontrol.Datacenter
+		pageSize := catalogPageSize
+		for pageNumber := 1; ; pageNumber++ {
+			page := pageNumber
+			listResp, err := c.client.GetDatacentersWithResponse(ctx, &missioncontrol.GetDatacentersParams{
+				PageNumber: &page,
+				PageSize:   &pageSize,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if listResp.StatusCode() != 200 || listResp.JSON200 == nil {
+				return nil, fmt.Errorf("unexpected response code %d getting datacenters", listResp.StatusCode())
+			}
+			datacenters = append(datacenters, listResp.JSON200.Data...)
+			// stop at the last page, also when the API ignores the page number
+			if len(listResp.JSON200.Data) < pageSize || !hasNextPage(listResp.JSON200.Meta) {
+				return datacenters, nil
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]missioncontrol.Datacenter), nil
+}
+
+// hasNextPage reports whether the pagination meta of a list response announces another page
+func hasNextPage(meta map[string]map[string]interface{}) bool {
+	return meta["pagination"]["nextPage"] != nil
+}
+
+// ServiceClasses returns all service classes.
+func (c *CatalogCache) ServiceClasses(ctx context.Context) ([]missioncontrol.ServiceClass, error) {
+	value, err := c.get(ctx, "serviceClasses", func(ctx context.Context) (interface{}, error) {
+		listResp, err := c.client.GetServiceClassesWithResponse(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		if listResp.StatusCode() != 200 || listResp.JSON200 == nil {
+			return nil, fmt.Errorf("unexpected response code %d getting service classes", listResp.StatusCode())
+		}
+		return listResp.JSON200.Data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]missioncontrol.ServiceClass), nil
+}
+
+// EventBrokerServiceVersions returns the broker versions available in a datacenter.
+func (c *CatalogCache) EventBrokerServiceVersions(ctx context.Context, datacenterId string) ([]missioncontrol.EventBrokerServiceVersion, error) {
+	value, err := c.get(ctx, "eventBrokerServiceVersions/"+datacenterId, func(ctx context.Context) (interface{}, error) {
+		listResp, err := c.client.GetEventBrokerServiceVersionsWithResponse(ctx, datacenterId)
+		if err != nil {
+			return nil, err
+		}
+		if listResp.StatusCode() != 200 || listResp.JSON200 == nil {
+			return nil, fmt.Errorf("unexpected response code %d getting broker versions of datacenter %s", listResp.StatusCode(), datacenterId)
+		}
+		return listResp.JSON200.Data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]missioncontrol.EventBrokerServiceVersion), nil
+}
@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"terraform-provider-gsolaceclustermgr/internal/missioncontrol"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogCacheSharesCalls(t *testing.T) {
+	var calls sync.Map
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counter, _ := calls.LoadOrStore(r.URL.Path, new(atomic.Int32))
+		counter.(*atomic.Int32).Add(1)
+		// slow answers, so concurrent lookups overlap
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/missionControl/datacenters":
+			_, _ = w.Write([]byte(`{"data":[{"id":"dc1","available":true,"datacenterType":"dedicated"}]}`))
+		case "/api/v2/missionControl/serviceClasses":
+			_, _ = w.Write([]byte(`{"data":[{"id":"DEVELOPER"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":[{"version":"1.2.3","supportedServiceClasses":["DEVELOPER"]}]}`))
+		}
+	}))
+	defer svr.Close()
+
+	client, err := missioncontrol.NewClientWithResponses(svr.URL)
+	assert.NoError(t, err)
+	cache := NewCatalogCache(client, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			datacenters, err := cache.Datacenters(context.Background())
+			assert.NoError(t, err)
+			assert.True(t, containsDatacenter(datacenters, "dc1"))
+			serviceClasses, err := cache.ServiceClasses(context.Background())
+			assert.NoError(t, err)
+			assert.True(t, containsServiceClass(serviceClasses, "DEVELOPER"))
+			versions, err := cache.EventBrokerServiceVersions(context.Background(), "dc1")
+			assert.NoError(t, err)
+			assert.True(t, containsVersion(versions, "1.2.3"))
+		}()
+	}
+	wg.Wait()
+
+	for _, p := range []string{
+		"/api/v2/missionControl/datacenters",
+		"/api/v2/missionControl/serviceClasses",
+		"/api/v2/missionControl/datacenters/dc1/eventBrokerServiceVersions",
+	} {
+		counter, ok := calls.Load(p)
+		if assert.True(t, ok, p) {
+			assert.Equal(t, int32(1), counter.(*atomic.Int32).Load(), "one call for "+p)
+		}
+	}
+}
+
+func TestCatalogCacheExpiresAndCachesErrors(t *testing.T) {
+	var calls atomic.Int32
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			http.Error(w, `{"message":"Forbidden","errorId":"403"}`, http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"id":"DEVELOPER"}]}`))
+	}))
+	defer svr.Close()
+
+	client, err := missioncontrol.NewClientWithResponses(svr.URL)
+	assert.NoError(t, err)
+	cache := NewCatalogCache(client, 30*time.Millisecond)
+
+	// errors are cached, every broker of a plan sees the same failure
+	_, err = cache.ServiceClasses(context.Background())
+	assert.Error(t, err)
+	_, err = cache.ServiceClasses(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+
+	time.Sleep(50 * time.Millisecond)
+	_, err = cache.ServiceClasses(context.Background())
+	assert.NoError(t, err)
+	_, err = cache.ServiceClasses(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load(), "expired entries are fetched again")
+}
+
+func TestCatalogCacheDatacenterPages(t *testing.T) {
+	// full pages, the page number decides whether the meta announces another page
+	fullPage := func(prefix string) string {
+		datacenters := make([]string, catalogPageSize)
+		for i := range datacenters {
+			datacenters[i] = fmt.Sprintf(`{"id":"%s%d"}`, prefix, i)
+		}
+		return strings.Join(datacenters, ",")
+	}
+	var calls atomic.Int32
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("pageNumber") == "1" {
+			_, _ = fmt.Fprintf(w, `{"data":[%s],"meta":{"pagination":{"pageNumber":1,"nextPage":2}}}`, fullPage("a"))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"data":[%s],"meta":{"pagination":{"pageNumber":2}}}`, fullPage("b"))
+	}))
+	defer svr.Close()
+
+	client, err := missioncontrol.NewClientWithResponses(svr.URL)
+	assert.NoError(t, err)
+	datacenters, err := NewCatalogCache(client, time.Minute).Datacenters(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, datacenters, 2*catalogPageSize)
+	assert.Equal(t, int32(2), calls.Load(), "no call after the page without nextPage")
+
+	// an API ignoring the page number returns the same full page without nextPage forever
+	calls.Store(0)
+	svr2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"data":[%s]}`, fullPage("a"))
+	}))
+	defer svr2.Close()
+
+	client, err = missioncontrol.NewClientWithResponses(svr2.URL)
+	assert.NoError(t, err)
+	datacenters, err = NewCatalogCache(client, time.Minute).Datacenters(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, datacenters, catalogPageSize)
+	assert.Equal(t, int32(1), calls.Load())
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"terraform-provider-gsolaceclustermgr/internal/missioncontrol"
+	"terraform-provider-gsolaceclustermgr/internal/waiter"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// operationFailedError is an operation MissionControl reported as FAILED, with the error details if available.
+type operationFailedError struct {
+	operation missioncontrol.Operation
+}
+
+func (e *operationFailedError) Error() string {
+	msg := fmt.Sprintf("operation %s failed", stringOrEmpty(e.operation.Id))
+	if e.operation.Error != nil {
+		if e.operation.Error.Message != nil {
+			msg += ": " + *e.operation.Error.Message
+		}
+		if e.operation.Error.ErrorId != nil {
+			msg += " (errorId " + *e.operation.Error.ErrorId + ")"
+		}
+	}
+	return msg
+}
+
+// waitForOperation polls the operation of a service until it SUCCEEDED or FAILED. A failed operation
+// is returned with an operationFailedError. The description completes "still ..." in the progress log.
+func (d CMProviderData) waitForOperation(ctx context.Context, serviceId string, operationId string, description string) (*missioncontrol.Operation, error) {
+	return d.pollOperation(ctx, serviceId, operationId, description, false)
+}
+
+// waitForDeletion waits for the delete operation of a service. The operations vanish with the
+// service, a 404 of the operation is confirmed by reading the service before the wait is over.
+func (d CMProviderData) waitForDeletion(ctx context.Context, serviceId string, operationId string) error {
+	_, err := d.pollOperation(ctx, serviceId, operationId, "deleting broker "+serviceId, true)
+	return err
+}
+
+// pollOperation returns the final operation, or nil when goneIsDone and the operation is not found
+func (d CMProviderData) pollOperation(ctx context.Context, serviceId string, operationId string, description string, goneIsDone bool) (*missioncontrol.Operation, error) {
+	var operation *missioncontrol.Operation
+	_, err := waiter.Wait(ctx, d.waiterConfig(), description, func(ctx context.Context) (string, bool, error) {
+		opResp, err := d.Client.GetServiceOperationWithResponse(ctx, serviceId, operationId)
+		if err != nil {
+			return "", false, err
+		}
+		if opResp.StatusCode() == 404 && goneIsDone {
+			// a wrong operation id or an operations endpoint hiccup must not pass for a deleted service
+			gone, err := d.serviceGone(ctx, serviceId)
+			if err != nil || !gone {
+				return "OPERATION NOT FOUND", false, err
+			}
+			operation = nil
+			return "GONE", true, nil
+		}
+		if opResp.StatusCode() != 200 || opResp.JSON200 == nil {
+			return "", false, fmt.Errorf("unexpected response code %d getting operation %s of broker service %s", opResp.StatusCode(), operationId, serviceId)
+		}
+		operation = &opResp.JSON200.Data
+		if operation.Status == nil {
+			return "", false, nil
+		}
+		status := *operation.Status
+		return string(status), status == missioncontrol.OperationStatusSUCCEEDED || status == missioncontrol.OperationStatusFAILED, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if operation != nil && *operation.Status == missioncontrol.OperationStatusFAILED {
+		return operation, &operationFailedError{operation: *operation}
+	}
+	return operation, nil
+}
+
+// serviceGone reads the service and reports whether MissionControl no longer knows it
+func (d CMProviderData) serviceGone(ctx context.Context, serviceId string) (bool, error) {
+	getResp, err := d.Client.GetServiceWithResponse(ctx, serviceId, nil)
+	if err != nil {
+		return false, err
+	}
+	// As of 20250127 the response is not as specified, so we cannot use getResp.JSON404
+	return getResp.StatusCode() == 404 && strings.Contains(string(getResp.Body), "Could not find event broker service with id"), nil
+}
+
+// operationError reads a failed operation once and returns its error details as operationFailedError.
+// The failure is reported even when the operation cannot be read.
+func (d CMProviderData) operationError(ctx context.Context, serviceId string, operationId string) error {
+	operation := missioncontrol.Operation{Id: &operationId}
+	opResp, err := d.Client.GetServiceOperationWithResponse(ctx, serviceId, operationId)
+	switch {
+	case err != nil:
+		tflog.Warn(ctx, fmt.Sprintf("Could not get failed operation %s of broker service %s: %s", operationId, serviceId, err.Error()))
+	case opResp.StatusCode() != 200 || opResp.JSON200 == nil:
+		tflog.Warn(ctx, fmt.Sprintf("Could not get failed operation %s of broker service %s, response code %d", operationId, serviceId, opResp.StatusCode()))
+	default:
+		operation = opResp.JSON200.Data
+	}
+	return &operationFailedError{operation: operation}
+}
+
+// waitErrorSummary titles timeouts as such, other errors get the summary of the action
+func waitErrorSummary(err error, summary string) string {
+	var timeoutErr *waiter.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return "Timeout"
+	}
+	return summary
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"terraform-provider-gsolaceclustermgr/internal/missioncontrol"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForOperation(t *testing.T) {
+	var polls atomic.Int32
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/missionControl/eventBrokerServices/svc1/operations/op1", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if polls.Add(1) < 3 {
+			_, _ = w.Write([]byte(`{"data":{"id":"op1","status":"INPROGRESS"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"op1","status":"SUCCEEDED"}}`))
+	}))
+	defer svr.Close()
+
+	client, err := missioncontrol.NewClientWithResponses(svr.URL)
+	assert.NoError(t, err)
+	providerData := CMProviderData{Client: client, PollingIntervalDuration: 5 * time.Millisecond}
+
+	operation, err := providerData.waitForOperation(context.Background(), "svc1", "op1", "creating broker svc1")
+	assert.NoError(t, err)
+	assert.Equal(t, missioncontrol.OperationStatusSUCCEEDED, *operation.Status)
+	assert.Equal(t, int32(3), polls.Load())
+}
+
+func TestWaitForOperationFailed(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"id":"op1","status":"FAILED","error":{"message":"No capacity left","errorId":"e-42"}}}`))
+	}))
+	defer svr.Close()
+
+	client, err := missioncontrol.NewClientWithResponses(svr.URL)
+	assert.NoError(t, err)
+	providerData := CMProviderData{Client: client, PollingIntervalDuration: 5 * time.Millisecond}
+
+	operation, err := providerData.waitForOperation(context.Background(), "svc1", "op1", "creating broker svc1")
+	var failedErr *operationFailedError
+	assert.True(t, errors.As(err, &failedErr))
+	assert.Equal(t, "operation op1 failed: No capacity left (errorId e-42)", err.Error())
+	assert.Equal(t, missioncontrol.OperationStatusFAILED, *operation.Status)
+	assert.Equal(t, "Error creating broker service", waitErrorSummary(err, "Error creating broker service"))
+}
+
+func TestWaitForDeletion(t *testing.T) {
+	var polls, serviceReads atomic.Int32
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v2/missionControl/eventBrokerServices/svc1" {
+			// the service is still there when the operation is first missing
+			if serviceReads.Add(1) < 2 {
+				_, _ = w.Write([]byte(`{"data":{"id":"svc1"}}`))
+				return
+			}
+			http.Error(w, `{"message":"Could not find event broker service with id svc1"}`, http.StatusNotFound)
+			return
+		}
+		if polls.Add(1) < 2 {
+			_, _ = w.Write([]byte(`{"data":{"id":"op1","status":"INPROGRESS"}}`))
+			return
+		}
+		// the operations vanish with the service
+		http.Error(w, `{"message":"Could not find operation with id op1"}`, http.StatusNotFound)
+	}))
+	defer svr.Close()
+
+	client, err := missioncontrol.NewClientWithResponses(svr.URL)
+	assert.NoError(t, err)
+	providerData := CMProviderData{Client: client, PollingIntervalDuration: 5 * time.Millisecond}
+
+	assert.NoError(t, providerData.waitForDeletion(context.Background(), "svc1", "op1"))
+	assert.Equal(t, int32(3), polls.Load(), "a missing operation of an existing service is polled again")
+	assert.Equal(t, int32(2), serviceReads.Load())
+
+	// other operations treat the 404 as an error
+	polls.Store(0)
+	_, err = providerData.waitForOperation(context.Background(), "svc1", "op1", "updating broker svc1")
+	assert.ErrorContains(t, err, "unexpected response code 404")
+}
+
+func TestWaitForDeletionServiceRemains(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v2/missionControl/eventBrokerServices/svc1" {
+			_, _ = w.Write([]byte(`{"data":{"id":"svc1"}}`))
+			return
+		}
+		// e.g. a wrong operation id
+		http.Error(w, `{"message":"Could not find operation with id op1"}`, http.StatusNotFound)
+	}))
+	defer svr.Close()
+
+	client, err := missioncontrol.NewClientWithResponses(svr.URL)
+	assert.NoError(t, err)
+	providerData := CMProviderData{Client: client, PollingIntervalDuration: 5 * time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = providerData.waitForDeletion(ctx, "svc1", "op1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "the deletion is not done while the service exists")
+	assert.ErrorContains(t, err, "last status OPERATION NOT FOUND")
+}
@@ -3,32 +3,70 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"terraform-provider-gsolaceclustermgr/internal/credentials"
 	"terraform-provider-gsolaceclustermgr/internal/missioncontrol"
+	"terraform-provider-gsolaceclustermgr/internal/transport"
+	"terraform-provider-gsolaceclustermgr/internal/waiter"
 	"time"
 
 	"net/http"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/providervalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // clusterManagerProviderModel maps provider schema data to a Go type.
 type clusterManagerProviderModel struct {
-	Host                    types.String `tfsdk:"host"`
-	BearerToken             types.String `tfsdk:"bearer_token"`
-	PollingTimeoutDuration  types.String `tfsdk:"polling_timeout_duration"`
-	PollingIntervalDuration types.String `tfsdk:"polling_interval_duration"`
+	Host                      types.String  `tfsdk:"host"`
+	Region                    types.String  `tfsdk:"region"`
+	Profile                   types.String  `tfsdk:"profile"`
+	CredentialsFile           types.String  `tfsdk:"credentials_file"`
+	BearerToken               types.String  `tfsdk:"bearer_token"`
+	BearerTokenFile           types.String  `tfsdk:"bearer_token_file"`
+	BearerTokenCommand        types.List    `tfsdk:"bearer_token_command"`
+	PollingTimeoutDuration    types.String  `tfsdk:"polling_timeout_duration"`
+	PollingIntervalDuration   types.String  `tfsdk:"polling_interval_duration"`
+	MaxRetries                types.Int32   `tfsdk:"max_retries"`
+	RequestTimeout            types.String  `tfsdk:"request_timeout"`
+	RequestsPerSecond         types.Float64 `tfsdk:"requests_per_second"`
+	Burst                     types.Int32   `tfsdk:"burst"`
+	MaxConcurrentProvisioning types.Int32   `tfsdk:"max_concurrent_provisioning"`
+	CACertFile                types.String  `tfsdk:"ca_cert_file"`
+	ClientCertFile            types.String  `tfsdk:"client_cert_file"`
+	ClientKeyFile             types.String  `tfsdk:"client_key_file"`
+	InsecureSkipVerify        types.Bool    `tfsdk:"insecure_skip_verify"`
+	ProxyURL                  types.String  `tfsdk:"proxy_url"`
+	SkipCredentialsValidation types.Bool    `tfsdk:"skip_credentials_validation"`
+	ReadOnly                  types.Bool    `tfsdk:"read_only"`
+	AuditLogPath              types.String  `tfsdk:"audit_log_path"`
+	HTTPTraceFile             types.String  `tfsdk:"http_trace_file"`
+	DefaultDatacenterId       types.String  `tfsdk:"default_datacenter_id"`
+	DefaultServiceClassId     types.String  `tfsdk:"default_serviceclass_id"`
+	DefaultEnvironmentId      types.String  `tfsdk:"default_environment_id"`
 }
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ provider.Provider = &clusterManagerProvider{}
+	_ provider.Provider                     = &clusterManagerProvider{}
+	_ provider.ProviderWithConfigValidators = &clusterManagerProvider{}
 )
 
 // New is a helper function to simplify provider server and testing implementation.
@@ -48,12 +86,51 @@ type clusterManagerProvider struct {
 	version string
 }
 
+// regionHosts maps the region shortcuts to the Solace Cloud API base URLs
+var regionHosts = map[string]string{
+	"us": "https://api.solace.cloud",
+	"eu": "https://api.solacecloud.eu",
+	"au": "https://api.solacecloud.com.au",
+}
+
 // providerdata for resources
 type CMProviderData struct {
 	Client                  *missioncontrol.ClientWithResponses
-	BearerToken             string
 	PollingIntervalDuration time.Duration
 	PollingTimeoutDuration  time.Duration
+	Deprecations            *transport.DeprecationNotices
+	ServiceLocks            *ServiceLocks
+	ProvisioningSlots       *ProvisioningSlots
+	StatusPoller            *StatusPoller
+	CatalogCache            *CatalogCache
+	// defaults for resources that do not set these attributes
+	DefaultDatacenterId   string
+	DefaultServiceClassId string
+	DefaultEnvironmentId  string
+}
+
+// addDeprecationWarnings reports the API deprecations seen since the last call, every deprecation is reported once per run
+func (d CMProviderData) addDeprecationWarnings(diagnostics *diag.Diagnostics) {
+	if d.Deprecations == nil {
+		return
+	}
+	for _, notice := range d.Deprecations.Drain() {
+		diagnostics.AddWarning(
+			"Deprecated MissionControl API",
+			fmt.Sprintf("The MissionControl API reported a deprecation for %s: %s\n\n", notice.Endpoint, notice.Detail)+
+				"Please check for a newer provider version or report this to the provider developers.",
+		)
+	}
+}
+
+// waiterConfig polls long running operations at the polling interval, backing off to four times the interval
+func (d CMProviderData) waiterConfig() waiter.Config {
+	return waiter.Config{
+		Interval:    d.PollingIntervalDuration,
+		MaxInterval: 4 * d.PollingIntervalDuration,
+		Multiplier:  1.5,
+		Jitter:      0.1,
+	}
 }
 
 // Metadata returns the provider type name.
@@ -67,22 +144,152 @@ func (p *clusterManagerProvider) Schema(_ context.Context, _ provider.SchemaRequ
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
-				Required: true,
+				MarkdownDescription: "Base URL of the MissionControl API, e.g. https://api.solace.cloud. Must not contain a path. Conflicts with `region`",
+				Optional:            true,
+				Validators: []validator.String{
+					hostValidator{},
+				},
+			},
+			"region": schema.StringAttribute{
+				MarkdownDescription: "Solace Cloud region (" + strings.Join(regionNames(), ", ") + ") used instead of `host`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(regionNames()...),
+				},
+			},
+			"profile": schema.StringAttribute{
+				MarkdownDescription: "Named profile in the shared credentials file providing host or region, token or token file and polling settings. Values set in the configuration or environment take precedence",
+				Optional:            true,
+			},
+			"credentials_file": schema.StringAttribute{
+				MarkdownDescription: "Shared credentials file (INI or YAML) containing the `profile`, a leading ~/ is expanded to the home directory. Defaults to ~/.config/solace/credentials",
+				Optional:            true,
 			},
 			"bearer_token": schema.StringAttribute{
-				Required:  true,
+				Optional:  true,
 				Sensitive: true,
 			},
+			"bearer_token_file": schema.StringAttribute{
+				MarkdownDescription: "File containing the bearer token. The file is read again when it changes or the API answers with 401, so rotated tokens are picked up",
+				Optional:            true,
+			},
+			"bearer_token_command": schema.ListAttribute{
+				MarkdownDescription: "Credential helper command (program and arguments) printing the bearer token, either as plain text or as JSON `{\"token\": \"...\", \"expiresAt\": \"<RFC3339>\"}`. The command is run again when the token expires or the API answers with 401",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
 			"polling_interval_duration": schema.StringAttribute{
 				Optional: true,
 			},
 			"polling_timeout_duration": schema.StringAttribute{
 				Optional: true,
 			},
+			"max_retries": schema.Int32Attribute{
+				MarkdownDescription: "Maximum number of retries for failed API calls (429, 502, 503, 504 and network errors). POST and PATCH are only retried on 429, and on 503 with a Retry-After header. Defaults to 4",
+				Optional:            true,
+				Validators: []validator.Int32{
+					int32validator.AtLeast(0),
+				},
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: "Timeout for a single API call attempt, e.g. 60s (default)",
+				Optional:            true,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: "Client side rate limit for API calls, shared by all resources and data sources. Defaults to 5",
+				Optional:            true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0.01),
+				},
+			},
+			"burst": schema.Int32Attribute{
+				MarkdownDescription: "Number of API calls that may exceed `requests_per_second` in a short burst. Defaults to 10",
+				Optional:            true,
+				Validators: []validator.Int32{
+					int32validator.AtLeast(1),
+				},
+			},
+			"max_concurrent_provisioning": schema.Int32Attribute{
+				MarkdownDescription: "Maximum number of brokers created or deleted at the same time, further brokers wait for a free slot. Defaults to 0 (unlimited)",
+				Optional:            true,
+				Validators: []validator.Int32{
+					int32validator.AtLeast(0),
+				},
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "PEM bundle with additional CA certificates to trust, e.g. for a TLS intercepting proxy",
+				Optional:            true,
+			},
+			"client_cert_file": schema.StringAttribute{
+				MarkdownDescription: "PEM client certificate for mutual TLS, requires `client_key_file`",
+				Optional:            true,
+			},
+			"client_key_file": schema.StringAttribute{
+				MarkdownDescription: "PEM private key of `client_cert_file`",
+				Optional:            true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				MarkdownDescription: "Disable verification of the server certificate. Do not use in production",
+				Optional:            true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "Proxy for API calls, e.g. http://proxy:3128. Defaults to the HTTPS_PROXY/NO_PROXY environment variables",
+				Optional:            true,
+			},
+			"skip_credentials_validation": schema.BoolAttribute{
+				MarkdownDescription: "Do not check host and token with an API call when the provider is configured, e.g. for offline plans",
+				Optional:            true,
+			},
+			"read_only": schema.BoolAttribute{
+				MarkdownDescription: "Reject all mutating API calls (create, update, delete) before they are sent, e.g. for plans in CI. Defaults to false",
+				Optional:            true,
+			},
+			"audit_log_path": schema.StringAttribute{
+				MarkdownDescription: "File the provider appends one JSON line to for every mutating API call, with timestamp, method, path, service id, operation id, HTTP status, duration and the redacted request body",
+				Optional:            true,
+			},
+			"http_trace_file": schema.StringAttribute{
+				MarkdownDescription: "Write all API traffic with credentials redacted to this HAR 1.2 file, e.g. to inspect it in the browser dev tools. The file is replaced on every run",
+				Optional:            true,
+			},
+			"default_datacenter_id": schema.StringAttribute{
+				MarkdownDescription: "Datacenter for brokers that do not set `datacenter_id`, applied when a broker is created, a changed default does not replace existing brokers",
+				Optional:            true,
+			},
+			"default_serviceclass_id": schema.StringAttribute{
+				MarkdownDescription: "Service class for brokers that do not set `serviceclass_id`, applied when a broker is created, a changed default does not replace existing brokers",
+				Optional:            true,
+			},
+			"default_environment_id": schema.StringAttribute{
+				MarkdownDescription: "Environment for brokers that do not set `environment_id`, applied when a broker is created, a changed default does not replace existing brokers",
+				Optional:            true,
+			},
 		},
 	}
 }
 
+// ConfigValidators ensures that at most one host and bearer token source is configured and client certificates are complete.
+func (p *clusterManagerProvider) ConfigValidators(_ context.Context) []provider.ConfigValidator {
+	return []provider.ConfigValidator{
+		providervalidator.Conflicting(
+			path.MatchRoot("host"),
+			path.MatchRoot("region"),
+		),
+		providervalidator.Conflicting(
+			path.MatchRoot("bearer_token"),
+			path.MatchRoot("bearer_token_file"),
+			path.MatchRoot("bearer_token_command"),
+		),
+		providervalidator.RequiredTogether(
+			path.MatchRoot("client_cert_file"),
+			path.MatchRoot("client_key_file"),
+		),
+	}
+}
+
 // Configure prepares a Solace MissionControl API client for data sources and resources.
 func (p *clusterManagerProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	tflog.Info(ctx, "Configuring ClusterManager Provider")
@@ -97,43 +304,132 @@ func (p *clusterManagerProvider) Configure(ctx context.Context, req provider.Con
 
 	// If practitioner provided a configuration value for any of the
 	// attributes, it must be a known value.
+	// Clients supporting deferred actions (e.g. Terraform Stacks) plan the resources
+	// again once the values are known, e.g. outputs of another module.
+	unknownSettings := unknownSettingAttributes(config)
+	if req.ClientCapabilities.DeferralAllowed && (hasUnknownConnectionConfig(config) || len(unknownSettings) > 0) {
+		tflog.Info(ctx, "MissionControl provider configuration is unknown, deferring")
+		resp.Deferred = &provider.Deferred{
+			Reason: provider.DeferredReasonProviderConfigUnknown,
+		}
+		return
+	}
 
-	if config.Host.IsUnknown() {
+	if config.Host.IsUnknown() || config.Region.IsUnknown() {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("host"),
 			"Unknown MissionControl API Host",
-			"The provider cannot create the MissionControl API client as there is an unknown configuration value for the MissionControl API host. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the MISSIONCONTROL_HOST environment variable.",
+			"The provider cannot create the MissionControl API client as there is an unknown configuration value for the MissionControl API host or region. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MISSIONCONTROL_HOST or MISSIONCONTROL_REGION environment variable.",
 		)
 	}
 
-	if config.BearerToken.IsUnknown() {
+	if config.BearerToken.IsUnknown() || config.BearerTokenFile.IsUnknown() || config.BearerTokenCommand.IsUnknown() {
 		resp.Diagnostics.AddAttributeError(
-			path.Root("password"),
+			path.Root("bearer_token"),
 			"Unknown MissionControl API Token",
 			"The provider cannot create the MissionControl API client as there is an unknown configuration value for the MissionControl API token. "+
 				"Either target apply the source of the value first, set the value statically in the configuration, or use the MISSIONCONTROL_TOKEN environment variable.",
 		)
 	}
 
+	if config.Profile.IsUnknown() || config.CredentialsFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("profile"),
+			"Unknown MissionControl Profile",
+			"The provider cannot create the MissionControl API client as there is an unknown configuration value for the profile or credentials file. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MISSIONCONTROL_PROFILE environment variable.",
+		)
+	}
+
+	for _, name := range unknownSettings {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(name),
+			"Unknown MissionControl Provider Setting",
+			"The provider cannot create the MissionControl API client as there is an unknown configuration value for "+name+". "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the corresponding environment variable.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// Default values to environment variables, but override
 	// with Terraform configuration value if set.
+	// Precedence: configuration, environment variables, profile, built-in defaults
 
 	host := os.Getenv("MISSIONCONTROL_HOST")
+	region := os.Getenv("MISSIONCONTROL_REGION")
 	bearerToken := os.Getenv("MISSIONCONTROL_TOKEN")
+	bearerTokenFile := os.Getenv("MISSIONCONTROL_TOKEN_FILE")
+	var bearerTokenCommand []string
 	pollingIntervalDurationStr := os.Getenv("POLLING_INTERVAL_DURATION")
 	pollingTimeoutDurationStr := os.Getenv("POLLING_TIMEOUT_DURATION")
+	maxRetriesStr := os.Getenv("MISSIONCONTROL_MAX_RETRIES")
+	requestTimeoutStr := os.Getenv("MISSIONCONTROL_REQUEST_TIMEOUT")
+	requestsPerSecondStr := os.Getenv("MISSIONCONTROL_REQUESTS_PER_SECOND")
+	burstStr := os.Getenv("MISSIONCONTROL_BURST")
+	maxConcurrentProvisioningStr := os.Getenv("MISSIONCONTROL_MAX_CONCURRENT_PROVISIONING")
+	skipCredentialsValidationStr := os.Getenv("MISSIONCONTROL_SKIP_CREDENTIALS_VALIDATION")
+	readOnlyStr := os.Getenv("MISSIONCONTROL_READ_ONLY")
+	auditLogPath := os.Getenv("MISSIONCONTROL_AUDIT_LOG_PATH")
+	httpTraceFile := os.Getenv("MISSIONCONTROL_HTTP_TRACE_FILE")
+	defaultDatacenterId := os.Getenv("MISSIONCONTROL_DEFAULT_DATACENTER_ID")
+	defaultServiceClassId := os.Getenv("MISSIONCONTROL_DEFAULT_SERVICECLASS_ID")
+	defaultEnvironmentId := os.Getenv("MISSIONCONTROL_DEFAULT_ENVIRONMENT_ID")
+	profileName := os.Getenv("MISSIONCONTROL_PROFILE")
+	credentialsFile := os.Getenv("MISSIONCONTROL_CREDENTIALS_FILE")
+
+	if !config.Profile.IsNull() {
+		profileName = config.Profile.ValueString()
+	}
+
+	if !config.CredentialsFile.IsNull() {
+		credentialsFile = config.CredentialsFile.ValueString()
+	}
 
-	if !config.Host.IsNull() {
+	// the profile only fills in values not set in the environment
+	if profileName != "" {
+		profile, err := loadProfile(credentialsFile, profileName)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("profile"),
+				"Invalid MissionControl Profile",
+				"The provider cannot create the MissionControl API client: "+err.Error(),
+			)
+			return
+		}
+		if host == "" && region == "" {
+			host = profile.Host
+			region = profile.Region
+		}
+		if bearerToken == "" && bearerTokenFile == "" {
+			bearerToken = profile.Token
+			bearerTokenFile = profile.TokenFile
+		}
+		if pollingIntervalDurationStr == "" {
+			pollingIntervalDurationStr = profile.PollingIntervalDuration
+		}
+		if pollingTimeoutDurationStr == "" {
+			pollingTimeoutDurationStr = profile.PollingTimeoutDuration
+		}
+		tflog.Info(ctx, fmt.Sprintf("Using MissionControl profile %s", profileName))
+	}
+
+	// host and region configured in HCL replace both environment variables
+	if !config.Host.IsNull() || !config.Region.IsNull() {
 		host = config.Host.ValueString()
+		region = config.Region.ValueString()
 	}
 
-	if !config.BearerToken.IsNull() {
+	// token sources configured in HCL replace all token sources from the environment
+	if !config.BearerToken.IsNull() || !config.BearerTokenFile.IsNull() || !config.BearerTokenCommand.IsNull() {
 		bearerToken = config.BearerToken.ValueString()
+		bearerTokenFile = config.BearerTokenFile.ValueString()
+		if !config.BearerTokenCommand.IsNull() {
+			resp.Diagnostics.Append(config.BearerTokenCommand.ElementsAs(ctx, &bearerTokenCommand, false)...)
+		}
 	}
 
 	if !config.PollingIntervalDuration.IsNull() {
@@ -144,32 +440,134 @@ func (p *clusterManagerProvider) Configure(ctx context.Context, req provider.Con
 		pollingTimeoutDurationStr = config.PollingTimeoutDuration.ValueString()
 	}
 
+	if !config.MaxRetries.IsNull() {
+		maxRetriesStr = strconv.Itoa(int(config.MaxRetries.ValueInt32()))
+	}
+
+	if !config.RequestTimeout.IsNull() {
+		requestTimeoutStr = config.RequestTimeout.ValueString()
+	}
+
+	if !config.RequestsPerSecond.IsNull() {
+		requestsPerSecondStr = strconv.FormatFloat(config.RequestsPerSecond.ValueFloat64(), 'f', -1, 64)
+	}
+
+	if !config.Burst.IsNull() {
+		burstStr = strconv.Itoa(int(config.Burst.ValueInt32()))
+	}
+
+	if !config.MaxConcurrentProvisioning.IsNull() {
+		maxConcurrentProvisioningStr = strconv.Itoa(int(config.MaxConcurrentProvisioning.ValueInt32()))
+	}
+
+	if !config.SkipCredentialsValidation.IsNull() {
+		skipCredentialsValidationStr = strconv.FormatBool(config.SkipCredentialsValidation.ValueBool())
+	}
+
+	if !config.ReadOnly.IsNull() {
+		readOnlyStr = strconv.FormatBool(config.ReadOnly.ValueBool())
+	}
+
+	if !config.AuditLogPath.IsNull() {
+		auditLogPath = config.AuditLogPath.ValueString()
+	}
+
+	if !config.HTTPTraceFile.IsNull() {
+		httpTraceFile = config.HTTPTraceFile.ValueString()
+	}
+
+	if !config.DefaultDatacenterId.IsNull() {
+		defaultDatacenterId = config.DefaultDatacenterId.ValueString()
+	}
+
+	if !config.DefaultServiceClassId.IsNull() {
+		defaultServiceClassId = config.DefaultServiceClassId.ValueString()
+	}
+
+	if !config.DefaultEnvironmentId.IsNull() {
+		defaultEnvironmentId = config.DefaultEnvironmentId.ValueString()
+	}
+
 	if pollingIntervalDurationStr == "" {
 		pollingIntervalDurationStr = "20s"
 	}
 	if pollingTimeoutDurationStr == "" {
 		pollingTimeoutDurationStr = "30m"
 	}
+	if maxRetriesStr == "" {
+		maxRetriesStr = strconv.Itoa(transport.DefaultMaxRetries)
+	}
+	if requestTimeoutStr == "" {
+		requestTimeoutStr = transport.DefaultRequestTimeout.String()
+	}
+	if requestsPerSecondStr == "" {
+		requestsPerSecondStr = strconv.FormatFloat(transport.DefaultRequestsPerSecond, 'f', -1, 64)
+	}
+	if burstStr == "" {
+		burstStr = strconv.Itoa(transport.DefaultBurst)
+	}
+	if maxConcurrentProvisioningStr == "" {
+		maxConcurrentProvisioningStr = "0"
+	}
+	if skipCredentialsValidationStr == "" {
+		skipCredentialsValidationStr = "false"
+	}
+	if readOnlyStr == "" {
+		readOnlyStr = "false"
+	}
 
 	// If any of the expected configurations are missing, return
 	// errors with provider-specific guidance.
 
-	if host == "" {
+	switch {
+	case host != "":
+		// values from the environment are not checked by the schema validators
+		if err := checkHost(host); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("host"),
+				"Invalid MissionControl API Host",
+				"The provider cannot create the MissionControl API client: "+err.Error(),
+			)
+		}
+		host = strings.TrimSuffix(host, "/")
+	case region != "":
+		if regionHost, ok := regionHosts[region]; ok {
+			host = regionHost
+		} else {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("region"),
+				"Invalid MissionControl API Region",
+				fmt.Sprintf("The provider cannot create the MissionControl API client as the region %q is unknown, use one of %s.", region, strings.Join(regionNames(), ", ")),
+			)
+		}
+	default:
 		resp.Diagnostics.AddAttributeError(
 			path.Root("host"),
 			"Missing MissionControl API Host",
 			"The provider cannot create the MissionControl API client as there is a missing or empty value for the MissionControl API host. "+
-				"Set the host value in the configuration or use the MISSIONCONTROL_HOST environment variable. "+
+				"Set the host or region value in the configuration or use the MISSIONCONTROL_HOST or MISSIONCONTROL_REGION environment variable. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
 
-	if bearerToken == "" {
+	var tokenSource credentials.TokenSource
+	var tokenSourceType string
+	switch {
+	case bearerToken != "":
+		tokenSource = credentials.NewStaticTokenSource(bearerToken)
+		tokenSourceType = "static"
+	case bearerTokenFile != "":
+		tokenSource = credentials.NewFileTokenSource(bearerTokenFile)
+		tokenSourceType = "file"
+	case len(bearerTokenCommand) > 0:
+		tokenSource = credentials.NewCommandTokenSource(bearerTokenCommand)
+		tokenSourceType = "command"
+	default:
 		resp.Diagnostics.AddAttributeError(
-			path.Root("bearerToken"),
-			"Missing MissionControl API PasTokensword",
+			path.Root("bearer_token"),
+			"Missing MissionControl API Token",
 			"The provider cannot create the MissionControl API client as there is a missing or empty value for the MissionControl API token. "+
-				"Set the password value in the configuration or use the MISSIONCONTROL_TOKEN environment variable. "+
+				"Set the bearer_token, bearer_token_file or bearer_token_command value in the configuration or use the MISSIONCONTROL_TOKEN or MISSIONCONTROL_TOKEN_FILE environment variable. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
@@ -192,24 +590,167 @@ func (p *clusterManagerProvider) Configure(ctx context.Context, req provider.Con
 		)
 	}
 
+	maxRetries, err := strconv.Atoi(maxRetriesStr)
+	if err != nil || maxRetries < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_retries"),
+			"Invalid max retries",
+			"The provider cannot create the MissionControl API client as the value is not a non-negative number. ",
+		)
+	}
+
+	requestTimeout, err := time.ParseDuration(requestTimeoutStr)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("request_timeout"),
+			"Invalid request timeout",
+			"The provider cannot create the MissionControl API client as the value cannot be parsed as a Duration. ",
+		)
+	}
+
+	requestsPerSecond, err := strconv.ParseFloat(requestsPerSecondStr, 64)
+	if err != nil || requestsPerSecond <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("requests_per_second"),
+			"Invalid requests per second",
+			"The provider cannot create the MissionControl API client as the value is not a positive number. ",
+		)
+	}
+
+	burst, err := strconv.Atoi(burstStr)
+	if err != nil || burst < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("burst"),
+			"Invalid burst",
+			"The provider cannot create the MissionControl API client as the value is not a positive number. ",
+		)
+	}
+
+	maxConcurrentProvisioning, err := strconv.Atoi(maxConcurrentProvisioningStr)
+	if err != nil || maxConcurrentProvisioning < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_concurrent_provisioning"),
+			"Invalid max concurrent provisioning",
+			"The provider cannot create the MissionControl API client as the value is not a non-negative number. ",
+		)
+	}
+
+	skipCredentialsValidation, err := strconv.ParseBool(skipCredentialsValidationStr)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("skip_credentials_validation"),
+			"Invalid skip credentials validation",
+			"The provider cannot create the MissionControl API client as the value cannot be parsed as a Bool. ",
+		)
+	}
+
+	readOnly, err := strconv.ParseBool(readOnlyStr)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("read_only"),
+			"Invalid read only",
+			"The provider cannot create the MissionControl API client as the value cannot be parsed as a Bool. ",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	ctx = tflog.SetField(ctx, "missioncontrol_host", host)
-	ctx = tflog.SetField(ctx, "missioncontrol_token", bearerToken)
-	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "missioncontrol_token")
+	ctx = tflog.SetField(ctx, "missioncontrol_token_source", tokenSourceType)
 	ctx = tflog.SetField(ctx, "polling_interval_duration", pollingIntervalDuration)
 	ctx = tflog.SetField(ctx, "polling_timeout_duration", pollingTimeoutDuration)
+	ctx = tflog.SetField(ctx, "max_retries", maxRetries)
+	ctx = tflog.SetField(ctx, "request_timeout", requestTimeout)
+	ctx = tflog.SetField(ctx, "requests_per_second", requestsPerSecond)
+	ctx = tflog.SetField(ctx, "burst", burst)
+	ctx = tflog.SetField(ctx, "read_only", readOnly)
+	ctx = tflog.SetField(ctx, "max_concurrent_provisioning", maxConcurrentProvisioning)
 
 	tflog.Info(ctx, fmt.Sprintf("Creating MissionControl client using %s", host))
 
 	// Create a new  client using the configuration values
-	// custom HTTP client
-	hc := http.Client{}
+	baseTransport, err := transport.NewBaseTransport(transport.BaseTransportOptions{
+		CACertFile:         config.CACertFile.ValueString(),
+		ClientCertFile:     config.ClientCertFile.ValueString(),
+		ClientKeyFile:      config.ClientKeyFile.ValueString(),
+		InsecureSkipVerify: config.InsecureSkipVerify.ValueBool(),
+		ProxyURL:           config.ProxyURL.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid TLS or proxy configuration",
+			"The provider cannot create the MissionControl API client: "+err.Error(),
+		)
+		return
+	}
+	if config.InsecureSkipVerify.ValueBool() {
+		tflog.Warn(ctx, "TLS certificate verification of the MissionControl API is disabled")
+	}
+
+	// every attempt is traced in the HAR file
+	var attemptTransport http.RoundTripper = baseTransport
+	if httpTraceFile != "" {
+		recorder, err := transport.NewHARRecorder(httpTraceFile, p.version)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("http_trace_file"),
+				"Invalid http trace file",
+				"The provider cannot create the MissionControl API client: "+err.Error(),
+			)
+			return
+		}
+		tflog.Info(ctx, fmt.Sprintf("Writing MissionControl API traffic to %s", httpTraceFile))
+		attemptTransport = &transport.HARTransport{Next: baseTransport, Recorder: recorder}
+	}
+
+	// custom HTTP client, transient failures are retried, every attempt is rate limited and logged (redacted)
+	hc := http.Client{
+		Transport: &transport.ReauthTransport{
+			Next: transport.NewRetryTransport(
+				&transport.RateLimitTransport{
+					Next:    &transport.LoggingTransport{Next: attemptTransport},
+					Limiter: transport.NewRateLimiter(requestsPerSecond, burst),
+				},
+				maxRetries, requestTimeout),
+			Tokens: tokenSource,
+		},
+	}
+
+	// deprecation hints are collected for all calls and reported as warnings
+	deprecations := transport.NewDeprecationNotices()
+	hc.Transport = &transport.DeprecationTransport{Next: hc.Transport, Notices: deprecations}
+
+	// mutating calls are audited once, including all retries
+	if auditLogPath != "" {
+		auditLog, err := transport.NewAuditLog(auditLogPath)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("audit_log_path"),
+				"Invalid audit log path",
+				"The provider cannot create the MissionControl API client: "+err.Error(),
+			)
+			return
+		}
+		tflog.Info(ctx, fmt.Sprintf("Writing audit log of mutating MissionControl calls to %s", auditLogPath))
+		hc.Transport = &transport.AuditTransport{Next: hc.Transport, Log: auditLog}
+	}
 
-	// TODO how to treat token...
-	client, err := missioncontrol.NewClientWithResponses(host, missioncontrol.WithHTTPClient(&hc))
+	// in read only mode mutating calls are rejected for all resources, before they reach the transport
+	var doer missioncontrol.HttpRequestDoer = &hc
+	if readOnly {
+		tflog.Info(ctx, "MissionControl client is read only")
+		doer = &missioncontrol.ReadOnlyDoer{Next: &hc}
+	}
+
+	// auth and user agent are added to every request, resources do not need to care
+	userAgent := fmt.Sprintf("terraform-provider-gsolaceclustermgr/%s terraform/%s", p.version, req.TerraformVersion)
+	client, err := missioncontrol.NewClientWithResponses(host,
+		missioncontrol.WithHTTPClient(doer),
+		missioncontrol.WithRequestEditorFn(bearerTokenRequestEditor(tokenSource)),
+		missioncontrol.WithRequestEditorFn(userAgentRequestEditor(userAgent)),
+	)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create MissionControl API Client",
@@ -220,14 +761,205 @@ func (p *clusterManagerProvider) Configure(ctx context.Context, req provider.Con
 		return
 	}
 
+	// fail early on wrong host or token, before resources are created
+	if skipCredentialsValidation {
+		tflog.Info(ctx, "Skipping validation of MissionControl credentials")
+	} else {
+		validateCredentials(ctx, client, tokenSource, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	// Make the MissionControl client available during DataSource and Resource
 	// type Configure methods.
-	resp.DataSourceData = CMProviderData{client, bearerToken, pollingIntervalDuration, pollingTimeoutDuration}
-	resp.ResourceData = CMProviderData{client, bearerToken, pollingIntervalDuration, pollingTimeoutDuration}
+	providerData := CMProviderData{
+		Client:                  client,
+		PollingIntervalDuration: pollingIntervalDuration,
+		PollingTimeoutDuration:  pollingTimeoutDuration,
+		Deprecations:            deprecations,
+		ServiceLocks:            NewServiceLocks(),
+		ProvisioningSlots:       NewProvisioningSlots(maxConcurrentProvisioning),
+		StatusPoller:            NewStatusPoller(client, pollingIntervalDuration),
+		CatalogCache:            NewCatalogCache(client, catalogCacheTTL),
+		DefaultDatacenterId:     defaultDatacenterId,
+		DefaultServiceClassId:   defaultServiceClassId,
+		DefaultEnvironmentId:    defaultEnvironmentId,
+	}
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
+	providerData.addDeprecationWarnings(&resp.Diagnostics)
 
 	tflog.Info(ctx, "Configured MissionControl client", map[string]any{"success": true})
 }
 
+// hasUnknownConnectionConfig reports unknown values of the attributes required to connect to the API
+func hasUnknownConnectionConfig(config clusterManagerProviderModel) bool {
+	return config.Host.IsUnknown() || config.Region.IsUnknown() ||
+		config.BearerToken.IsUnknown() || config.BearerTokenFile.IsUnknown() || config.BearerTokenCommand.IsUnknown() ||
+		config.Profile.IsUnknown() || config.CredentialsFile.IsUnknown()
+}
+
+// unknownSettingAttributes returns the names of the other unknown attributes, they change the client or the broker defaults
+func unknownSettingAttributes(config clusterManagerProviderModel) []string {
+	settings := map[string]attr.Value{
+		"polling_timeout_duration":    config.PollingTimeoutDuration,
+		"polling_interval_duration":   config.PollingIntervalDuration,
+		"max_retries":                 config.MaxRetries,
+		"request_timeout":             config.RequestTimeout,
+		"requests_per_second":         config.RequestsPerSecond,
+		"burst":                       config.Burst,
+		"max_concurrent_provisioning": config.MaxConcurrentProvisioning,
+		"ca_cert_file":                config.CACertFile,
+		"client_cert_file":            config.ClientCertFile,
+		"client_key_file":             config.ClientKeyFile,
+		"insecure_skip_verify":        config.InsecureSkipVerify,
+		"proxy_url":                   config.ProxyURL,
+		"skip_credentials_validation": config.SkipCredentialsValidation,
+		"read_only":                   config.ReadOnly,
+		"audit_log_path":              config.AuditLogPath,
+		"http_trace_file":             config.HTTPTraceFile,
+		"default_datacenter_id":       config.DefaultDatacenterId,
+		"default_serviceclass_id":     config.DefaultServiceClassId,
+		"default_environment_id":      config.DefaultEnvironmentId,
+	}
+	var unknown []string
+	for name, value := range settings {
+		if value.IsUnknown() {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// validateCredentials makes a cheap authenticated API call and reports failures on the host or bearer_token attribute
+func validateCredentials(ctx context.Context, client *missioncontrol.ClientWithResponses, tokenSource credentials.TokenSource, diagnostics *diag.Diagnostics) {
+	const skipHint = "\n\nSet skip_credentials_validation = true to skip this check, e.g. for offline plans."
+
+	if _, err := tokenSource.Token(ctx); err != nil {
+		diagnostics.AddAttributeError(
+			path.Root("bearer_token"),
+			"Cannot get MissionControl API Token",
+			"The provider cannot get the MissionControl API token: "+err.Error(),
+		)
+		return
+	}
+
+	versionsResp, err := client.GetVersionsWithResponse(ctx)
+	if err != nil {
+		diagnostics.AddAttributeError(
+			path.Root("host"),
+			"Cannot reach MissionControl API",
+			"The provider cannot reach the MissionControl API, check the host value: "+err.Error()+skipHint,
+		)
+		return
+	}
+
+	switch versionsResp.StatusCode() {
+	case 200:
+		tflog.Info(ctx, "Validated MissionControl credentials")
+	case 401, 403:
+		var errMsg string
+		if versionsResp.JSON401 != nil && versionsResp.JSON401.Message != nil {
+			errMsg = *(versionsResp.JSON401.Message)
+		} else {
+			errMsg = parseErrorDTO(versionsResp.Body)
+		}
+		diagnostics.AddAttributeError(
+			path.Root("bearer_token"),
+			"Invalid MissionControl API Token",
+			fmt.Sprintf("The MissionControl API rejected the bearer token (HTTP %d): %s", versionsResp.StatusCode(), errMsg)+skipHint,
+		)
+	default:
+		diagnostics.AddAttributeError(
+			path.Root("host"),
+			"Unexpected MissionControl API response",
+			fmt.Sprintf("The MissionControl API answered with HTTP %d, check the host value.", versionsResp.StatusCode())+skipHint,
+		)
+	}
+}
+
+// bearerTokenRequestEditor adds the auth header, the token is taken from the provider-level source on every request
+func bearerTokenRequestEditor(tokenSource credentials.TokenSource) missioncontrol.RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		token, err := tokenSource.Token(ctx)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+}
+
+// userAgentRequestEditor identifies the provider traffic towards solace
+func userAgentRequestEditor(userAgent string) missioncontrol.RequestEditorFn {
+	return func(_ context.Context, req *http.Request) error {
+		req.Header.Set("User-Agent", userAgent)
+		return nil
+	}
+}
+
+// loadProfile reads a profile from the given or the default credentials file
+func loadProfile(credentialsFile string, profileName string) (*credentials.Profile, error) {
+	if credentialsFile == "" {
+		defaultFile, err := credentials.DefaultCredentialsFile()
+		if err != nil {
+			return nil, err
+		}
+		credentialsFile = defaultFile
+	}
+	return credentials.LoadProfile(credentialsFile, profileName)
+}
+
+// regionNames returns the supported region shortcuts, sorted
+func regionNames() []string {
+	names := make([]string, 0, len(regionHosts))
+	for name := range regionHosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkHost accepts absolute http(s) URLs without path, query or fragment
+func checkHost(host string) error {
+	u, err := url.Parse(host)
+	if err != nil {
+		return fmt.Errorf("%q is not a valid URL: %w", host, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%q must start with https:// or http://", host)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%q has no host name", host)
+	}
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("%q must not contain a path, query or fragment, use the base URL like %s", host, regionHosts["us"])
+	}
+	return nil
+}
+
+// hostValidator checks the host attribute with checkHost
+type hostValidator struct{}
+
+func (v hostValidator) Description(_ context.Context) string {
+	return "value must be an absolute http(s) URL without path"
+}
+
+func (v hostValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v hostValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if err := checkHost(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid MissionControl API Host", err.Error())
+	}
+}
+
 // DataSources defines the data sources implemented in the provider.
 func (p *clusterManagerProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
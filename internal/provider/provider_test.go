@@ -1,8 +1,24 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"terraform-provider-gsolaceclustermgr/internal/credentials"
+	"terraform-provider-gsolaceclustermgr/internal/transport"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
 )
 
 const (
@@ -26,3 +42,273 @@ var (
 		"gsolaceclustermgr": providerserver.NewProtocol6WithError(New("test")()),
 	}
 )
+
+func TestRequestEditors(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:8091/api/v2/missionControl/eventBrokerServices", nil)
+
+	assert.NoError(t, bearerTokenRequestEditor(credentials.NewStaticTokenSource("bt42"))(context.Background(), req))
+	assert.NoError(t, userAgentRequestEditor("terraform-provider-gsolaceclustermgr/test terraform/1.9.0")(context.Background(), req))
+
+	assert.Equal(t, "Bearer bt42", req.Header.Get("Authorization"))
+	assert.Equal(t, "terraform-provider-gsolaceclustermgr/test terraform/1.9.0", req.Header.Get("User-Agent"))
+}
+
+func TestAccProviderCredentialsValidation(t *testing.T) {
+	if os.Getenv("FAKE_SERVER_EXT") == "" {
+		startFakeServer()
+		defer stopFakeServer()
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "gsolaceclustermgr" {
+					bearer_token = "invalid"
+					host = "http://localhost:8091"
+				}
+				data "gsolaceclustermgr_broker" "invalidtoken" {
+					id = "NotExisting1"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Invalid MissionControl API Token"),
+			},
+			{
+				// validation skipped, the data source call fails instead
+				Config: `
+				provider "gsolaceclustermgr" {
+					bearer_token = "invalid"
+					host = "http://localhost:8091"
+					skip_credentials_validation = true
+				}
+				data "gsolaceclustermgr_broker" "invalidtoken" {
+					id = "NotExisting1"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Error getting broker service"),
+			},
+		},
+	})
+}
+
+func TestCheckHost(t *testing.T) {
+	assert.NoError(t, checkHost("https://api.solace.cloud"))
+	assert.NoError(t, checkHost("https://api.solace.cloud/"))
+	assert.NoError(t, checkHost("http://localhost:8091"))
+
+	assert.Error(t, checkHost("api.solace.cloud"))
+	assert.Error(t, checkHost("ftp://api.solace.cloud"))
+	assert.Error(t, checkHost("https://api.solace.cloud/api/v2/missionControl"))
+	assert.Error(t, checkHost("https://api.solace.cloud?x=1"))
+}
+
+func TestAccProviderHostValidation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "gsolaceclustermgr" {
+					bearer_token = "bt42"
+					host = "https://api.solace.cloud/api/v2/missionControl"
+				}
+				data "gsolaceclustermgr_broker" "invalidhost" {
+					id = "NotExisting1"
+				}
+				`,
+				ExpectError: regexp.MustCompile("must not contain a path"),
+			},
+			{
+				Config: `
+				provider "gsolaceclustermgr" {
+					bearer_token = "bt42"
+					region = "mars"
+				}
+				data "gsolaceclustermgr_broker" "invalidregion" {
+					id = "NotExisting1"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Invalid Attribute Value Match"),
+			},
+			{
+				Config: `
+				provider "gsolaceclustermgr" {
+					bearer_token = "bt42"
+					host = "http://localhost:8091"
+					region = "eu"
+				}
+				data "gsolaceclustermgr_broker" "conflicting" {
+					id = "NotExisting1"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+		},
+	})
+}
+
+func TestAccProviderProfile(t *testing.T) {
+	if os.Getenv("FAKE_SERVER_EXT") == "" {
+		startFakeServer()
+		defer stopFakeServer()
+	}
+	credentialsFile := filepath.Join(t.TempDir(), "credentials")
+	assert.NoError(t, os.WriteFile(credentialsFile, []byte(`
+[fake]
+host = http://localhost:8091
+token = bt42
+
+[invalidtoken]
+host = http://localhost:8091
+token = invalid
+`), 0600))
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				provider "gsolaceclustermgr" {
+					profile = "invalidtoken"
+					credentials_file = %q
+				}
+				data "gsolaceclustermgr_broker" "profile" {
+					id = "NotExisting1"
+				}
+				`, credentialsFile),
+				ExpectError: regexp.MustCompile("Invalid MissionControl API Token"),
+			},
+			{
+				// the token in the configuration takes precedence over the profile
+				Config: fmt.Sprintf(`
+				provider "gsolaceclustermgr" {
+					profile = "invalidtoken"
+					credentials_file = %q
+					bearer_token = "bt42"
+				}
+				data "gsolaceclustermgr_broker" "profile" {
+					id = "NotExisting1"
+				}
+				`, credentialsFile),
+				ExpectError: regexp.MustCompile("Error getting broker service"),
+			},
+			{
+				Config: fmt.Sprintf(`
+				provider "gsolaceclustermgr" {
+					profile = "missing"
+					credentials_file = %q
+				}
+				data "gsolaceclustermgr_broker" "profile" {
+					id = "NotExisting1"
+				}
+				`, credentialsFile),
+				ExpectError: regexp.MustCompile(`profile "missing" not found`),
+			},
+		},
+	})
+}
+
+func TestAccProviderReadOnly(t *testing.T) {
+	if os.Getenv("FAKE_SERVER_EXT") == "" {
+		startFakeServer()
+		defer stopFakeServer()
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "gsolaceclustermgr" {
+					bearer_token = "bt42"
+					host = "http://localhost:8091"
+					read_only = true
+				}
+				resource "gsolaceclustermgr_broker" "readonly" {
+					serviceclass_id = "DEVELOPER"
+					name = "ocs-prov-readonly"
+					datacenter_id = "aks-germanywestcentral"
+				}
+				`,
+				ExpectError: regexp.MustCompile("configured read_only"),
+			},
+		},
+	})
+}
+
+func TestConfigureDeferredOnUnknownHost(t *testing.T) {
+	ctx := context.Background()
+	p := New("test")()
+	schemaResp := provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	// all attributes null, except the unknown host
+	schemaType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, attrType := range schemaType.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	values["host"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	config := tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaType, values)}
+
+	resp := provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{Config: config, ClientCapabilities: provider.ConfigureProviderClientCapabilities{DeferralAllowed: true}}, &resp)
+	assert.False(t, resp.Diagnostics.HasError())
+	assert.Equal(t, &provider.Deferred{Reason: provider.DeferredReasonProviderConfigUnknown}, resp.Deferred)
+	assert.Nil(t, resp.ResourceData)
+
+	// without deferral support an unknown host is still an error
+	resp = provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{Config: config}, &resp)
+	assert.True(t, resp.Diagnostics.HasError())
+	assert.Nil(t, resp.Deferred)
+}
+
+func TestConfigureDeferredOnUnknownSettings(t *testing.T) {
+	ctx := context.Background()
+	p := New("test")()
+	schemaResp := provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	schemaType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	for _, name := range []string{"default_datacenter_id", "default_serviceclass_id", "ca_cert_file", "proxy_url", "read_only"} {
+		// known host and token, only the setting is unknown
+		values := map[string]tftypes.Value{}
+		for attrName, attrType := range schemaType.AttributeTypes {
+			values[attrName] = tftypes.NewValue(attrType, nil)
+		}
+		values["host"] = tftypes.NewValue(tftypes.String, "http://localhost:8091")
+		values["bearer_token"] = tftypes.NewValue(tftypes.String, "bt42")
+		values[name] = tftypes.NewValue(schemaType.AttributeTypes[name], tftypes.UnknownValue)
+		config := tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaType, values)}
+
+		resp := provider.ConfigureResponse{}
+		p.Configure(ctx, provider.ConfigureRequest{Config: config, ClientCapabilities: provider.ConfigureProviderClientCapabilities{DeferralAllowed: true}}, &resp)
+		assert.False(t, resp.Diagnostics.HasError(), name)
+		assert.Equal(t, &provider.Deferred{Reason: provider.DeferredReasonProviderConfigUnknown}, resp.Deferred, name)
+		assert.Nil(t, resp.ResourceData, name)
+
+		resp = provider.ConfigureResponse{}
+		p.Configure(ctx, provider.ConfigureRequest{Config: config}, &resp)
+		if assert.Equal(t, 1, resp.Diagnostics.ErrorsCount(), name) {
+			assert.Equal(t, "Unknown MissionControl Provider Setting", resp.Diagnostics.Errors()[0].Summary(), name)
+		}
+		assert.Nil(t, resp.ResourceData, name)
+	}
+}
+
+func TestAddDeprecationWarnings(t *testing.T) {
+	providerData := CMProviderData{Deprecations: transport.NewDeprecationNotices()}
+	notice := transport.DeprecationNotice{Endpoint: "GET /api/v2/missionControl/eventBrokerServices/{id}", Detail: "will be removed on Wed, 31 Dec 2025 23:59:59 GMT"}
+	providerData.Deprecations.Add(notice)
+	providerData.Deprecations.Add(notice)
+
+	var diags diag.Diagnostics
+	providerData.addDeprecationWarnings(&diags)
+	assert.Equal(t, 1, diags.WarningsCount())
+	assert.Contains(t, diags.Warnings()[0].Detail(), "GET /api/v2/missionControl/eventBrokerServices/{id}")
+
+	// reported once per run
+	providerData.Deprecations.Add(notice)
+	providerData.addDeprecationWarnings(&diags)
+	assert.Equal(t, 1, diags.WarningsCount())
+}
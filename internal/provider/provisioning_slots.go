@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ProvisioningSlots limits the number of brokers created or deleted at the same time,
+// MissionControl and the datacenter quotas reject too many concurrent provisioning operations.
+type ProvisioningSlots struct {
+	slots chan struct{}
+}
+
+// NewProvisioningSlots creates max slots, 0 means unlimited.
+func NewProvisioningSlots(max int) *ProvisioningSlots {
+	if max <= 0 {
+		return &ProvisioningSlots{}
+	}
+	return &ProvisioningSlots{slots: make(chan struct{}, max)}
+}
+
+// Acquire waits for a free slot, the returned function releases it.
+func (s *ProvisioningSlots) Acquire(ctx context.Context) (func(), error) {
+	if s == nil || s.slots == nil {
+		return func() {}, nil
+	}
+	start := time.Now()
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for a provisioning slot: %w", ctx.Err())
+	}
+	waited := time.Since(start)
+	tflog.Info(ctx, fmt.Sprintf("Acquired provisioning slot after %s", waited.Round(time.Millisecond)), map[string]interface{}{
+		"wait_ms":           waited.Milliseconds(),
+		"max_provisioning":  cap(s.slots),
+		"used_provisioning": len(s.slots),
+	})
+	return func() { <-s.slots }, nil
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvisioningSlots(t *testing.T) {
+	ctx := context.Background()
+	slots := NewProvisioningSlots(2)
+
+	release1, err := slots.Acquire(ctx)
+	assert.NoError(t, err)
+	release2, err := slots.Acquire(ctx)
+	assert.NoError(t, err)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = slots.Acquire(timeoutCtx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	release1()
+	release3, err := slots.Acquire(ctx)
+	assert.NoError(t, err)
+	release2()
+	release3()
+}
+
+func TestProvisioningSlotsUnlimited(t *testing.T) {
+	slots := NewProvisioningSlots(0)
+	for i := 0; i < 100; i++ {
+		_, err := slots.Acquire(context.Background())
+		assert.NoError(t, err)
+	}
+}
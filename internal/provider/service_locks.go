@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sync"
+	"terraform-provider-gsolaceclustermgr/internal/waiter"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ServiceLocks serialises the mutating calls on one event broker service,
+// MissionControl rejects overlapping operations on the same service.
+type ServiceLocks struct {
+	mu    sync.Mutex
+	locks map[string]chan struct{}
+}
+
+func NewServiceLocks() *ServiceLocks {
+	return &ServiceLocks{locks: map[string]chan struct{}{}}
+}
+
+// Lock waits until the service is free, the returned function releases it.
+func (l *ServiceLocks) Lock(ctx context.Context, serviceId string) (func(), error) {
+	l.mu.Lock()
+	lock, ok := l.locks[serviceId]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		l.locks[serviceId] = lock
+	}
+	l.mu.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+		return func() { <-lock }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// answers to a call while another operation is running on the service
+var conflictMessagePattern = regexp.MustCompile(`(?i)(ongoing|in progress|another|pending) operation`)
+
+func isConflict(statusCode int, body []byte) bool {
+	return statusCode == http.StatusConflict ||
+		(statusCode == http.StatusBadRequest && conflictMessagePattern.Match(body))
+}
+
+// mutateService runs a mutating API call with the service lock held. When the call conflicts with an
+// operation started elsewhere, e.g. in the console, it is repeated once the service has no ongoing operations.
+// The call returns the status code and body of the response. The waits end with the context.
+func (d CMProviderData) mutateService(ctx context.Context, serviceId string, call func() (int, []byte, error)) error {
+	unlock, err := d.ServiceLocks.Lock(ctx, serviceId)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	for {
+		statusCode, body, err := call()
+		if err != nil || !isConflict(statusCode, body) {
+			return err
+		}
+		tflog.Info(ctx, fmt.Sprintf("Conflicting operation on broker service %s, waiting for ongoing operations to finish", serviceId))
+		if err := d.waitForNoOngoingOperations(ctx, serviceId); err != nil {
+			return err
+		}
+	}
+}
+
+// waitForNoOngoingOperations polls the service until its OngoingOperationIds are empty
+func (d CMProviderData) waitForNoOngoingOperations(ctx context.Context, serviceId string) error {
+	_, err := waiter.Wait(ctx, d.waiterConfig(), "waiting for the ongoing operations on broker service "+serviceId, func(ctx context.Context) (string, bool, error) {
+		getResp, err := d.Client.GetServiceWithResponse(ctx, serviceId, nil)
+		if err != nil {
+			return "", false, err
+		}
+		if getResp.StatusCode() != 200 || getResp.JSON200 == nil {
+			return "", false, fmt.Errorf("unexpected response code %d getting broker service %s", getResp.StatusCode(), serviceId)
+		}
+		ongoing := getResp.JSON200.Data.OngoingOperationIds
+		if ongoing == nil || len(*ongoing) == 0 {
+			return "no ongoing operations", true, nil
+		}
+		return fmt.Sprintf("ongoing operations %v", *ongoing), false, nil
+	})
+	return err
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"terraform-provider-gsolaceclustermgr/internal/missioncontrol"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceLocks(t *testing.T) {
+	locks := NewServiceLocks()
+	ctx := context.Background()
+
+	unlock, err := locks.Lock(ctx, "svc1")
+	assert.NoError(t, err)
+
+	// other services are not blocked
+	unlock2, err := locks.Lock(ctx, "svc2")
+	assert.NoError(t, err)
+	unlock2()
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = locks.Lock(timeoutCtx, "svc1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	unlock()
+	unlock, err = locks.Lock(ctx, "svc1")
+	assert.NoError(t, err)
+	unlock()
+}
+
+func TestMutateServiceRetriesConflicts(t *testing.T) {
+	var patches, gets atomic.Int32
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodPatch:
+			if patches.Add(1) == 1 {
+				http.Error(w, `{"message":"Event broker service svc1 has an ongoing operation"}`, http.StatusConflict)
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"id":"svc1"}}`))
+		case http.MethodGet:
+			if gets.Add(1) == 1 {
+				_, _ = w.Write([]byte(`{"data":{"id":"svc1","ongoingOperationIds":["op1"]}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"id":"svc1","ongoingOperationIds":[]}}`))
+		}
+	}))
+	defer svr.Close()
+
+	client, err := missioncontrol.NewClientWithResponses(svr.URL)
+	assert.NoError(t, err)
+	providerData := CMProviderData{
+		Client:                  client,
+		PollingIntervalDuration: 10 * time.Millisecond,
+		PollingTimeoutDuration:  time.Second,
+		ServiceLocks:            NewServiceLocks(),
+	}
+
+	name := "renamed"
+	var statusCode int
+	err = providerData.mutateService(context.Background(), "svc1", func() (int, []byte, error) {
+		updateResp, err := client.UpdateServiceWithResponse(context.Background(), "svc1", missioncontrol.UpdateServiceJSONRequestBody{Name: &name})
+		if err != nil {
+			return 0, nil, err
+		}
+		statusCode = updateResp.StatusCode()
+		return updateResp.StatusCode(), updateResp.Body, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, int32(2), patches.Load())
+	assert.Equal(t, int32(2), gets.Load(), "waits until the ongoing operations are drained")
+}
+
+func TestIsConflict(t *testing.T) {
+	assert.True(t, isConflict(http.StatusConflict, nil))
+	assert.True(t, isConflict(http.StatusBadRequest, []byte(`<ErrorDTO><message>Another operation is in progress</message></ErrorDTO>`)))
+	assert.False(t, isConflict(http.StatusBadRequest, []byte(`<ErrorDTO><message>Invalid name</message></ErrorDTO>`)))
+	assert.False(t, isConflict(http.StatusOK, nil))
+}
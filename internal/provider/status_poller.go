@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"terraform-provider-gsolaceclustermgr/internal/missioncontrol"
+	"terraform-provider-gsolaceclustermgr/internal/waiter"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// page size of the status scan, the maximum supported by the API
+const statusPollerPageSize = 100
+
+// bounds one scan, also when the API keeps announcing more pages
+const statusPollerMaxPages = 100
+
+// StatusPoller waits for the creation of brokers. All brokers created in one apply share
+// one GetServices page scan per polling interval, instead of an expanded GetService each.
+type StatusPoller struct {
+	client *missioncontrol.ClientWithResponses
+	// interval of the scans, failed scans back off
+	scanWait waiter.Config
+
+	mu      sync.Mutex
+	waiters map[string][]chan missioncontrol.ServiceCreationState
+	running bool
+}
+
+func NewStatusPoller(client *missioncontrol.ClientWithResponses, interval time.Duration) *StatusPoller {
+	return &StatusPoller{
+		client: client,
+		scanWait: waiter.Config{
+			Interval:    interval,
+			MaxInterval: 8 * interval,
+			Multiplier:  2,
+			Jitter:      0.1,
+		},
+		waiters: map[string][]chan missioncontrol.ServiceCreationState{},
+	}
+}
+
+// WaitForCreation blocks until the service is COMPLETED or FAILED, or the context is done.
+// The progress is logged and a timeout reports the last observed state.
+func (p *StatusPoller) WaitForCreation(ctx context.Context, serviceId string) (missioncontrol.ServiceCreationState, error) {
+	// receives every observed state, only the latest is kept
+	states := make(chan missioncontrol.ServiceCreationState, 1)
+
+	p.mu.Lock()
+	p.waiters[serviceId] = append(p.waiters[serviceId], states)
+	if !p.running {
+		p.running = true
+		// the scan serves all waiters, it must not end with the request that started it
+		go p.run(context.WithoutCancel(ctx))
+	}
+	p.mu.Unlock()
+	defer p.remove(serviceId, states)
+
+	// the scans set the pace, every refresh waits for the next observed state
+	state, err := waiter.Wait(ctx, waiter.Config{}, "creating broker "+serviceId, func(ctx context.Context) (string, bool, error) {
+		select {
+		case state := <-states:
+			return string(state), isFinalCreationState(state), nil
+		case <-ctx.Done():
+			return "", false, ctx.Err()
+		}
+	})
+	return missioncontrol.ServiceCreationState(state), err
+}
+
+func isFinalCreationState(state missioncontrol.ServiceCreationState) bool {
+	return state == missioncontrol.ServiceCreationStateCOMPLETED || state == missioncontrol.ServiceCreationStateFAILED
+}
+
+func (p *StatusPoller) remove(serviceId string, states chan missioncontrol.ServiceCreationState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	waiters := p.waiters[serviceId]
+	for i, w := range waiters {
+		if w == states {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waiters) == 0 {
+		delete(p.waiters, serviceId)
+	} else {
+		p.waiters[serviceId] = waiters
+	}
+}
+
+// run scans all services every interval and stops when nobody is waiting anymore
+func (p *StatusPoller) run(ctx context.Context) {
+	failures := 0
+	for {
+		// the context is never cancelled, the scan ends when the waiters are gone
+		_ = waiter.Sleep(ctx, p.scanWait.Delay(failures))
+
+		p.mu.Lock()
+		pending := len(p.waiters)
+		if pending == 0 {
+			p.running = false
+			p.mu.Unlock()
+			return
+		}
+		p.mu.Unlock()
+
+		tflog.Debug(ctx, fmt.Sprintf("Checking broker status of %d pending brokers", pending))
+		states, err := p.scan(ctx)
+		if err != nil {
+			// transient errors have already been retried by the client, try again with backoff
+			tflog.Warn(ctx, "Could not get broker status: "+err.Error())
+			failures++
+			continue
+		}
+		failures = 0
+		p.notify(ctx, states)
+	}
+}
+
+// scan returns the creation state of all services, pages are read until all pending services are found
+// or the last page is reached. Services not listed yet are looked for again in the next scan.
+func (p *StatusPoller) scan(ctx context.Context) (map[string]missioncontrol.ServiceCreationState, error) {
+	states := map[string]missioncontrol.ServiceCreationState{}
+	pageSize := statusPollerPageSize
+	for pageNumber := 1; pageNumber <= statusPollerMaxPages; pageNumber++ {
+		page := pageNumber
+		listResp, err := p.client.GetServicesWithResponse(ctx, &missioncontrol.GetServicesParams{
+			PageNumber: &page,
+			PageSize:   &pageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if listResp.StatusCode() != 200 || listResp.JSON200 == nil {
+			return nil, fmt.Errorf("unexpected response code: %v", listResp.StatusCode())
+		}
+		for _, service := range listResp.JSON200.Data {
+			if service.Id != nil && service.CreationState != nil {
+				states[*service.Id] = *service.CreationState
+			}
+		}
+		// stop at the last page, also when the API ignores the page number
+		if len(listResp.JSON200.Data) < pageSize || !hasNextPage(listResp.JSON200.Meta) || p.allFound(states) {
+			return states, nil
+		}
+	}
+	tflog.Warn(ctx, fmt.Sprintf("Broker status scan stopped after %d pages", statusPollerMaxPages))
+	return states, nil
+}
+
+func (p *StatusPoller) allFound(states map[string]missioncontrol.ServiceCreationState) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for serviceId := range p.waiters {
+		if _, ok := states[serviceId]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// notify hands the observed states to the waiters, services not listed yet keep waiting
+func (p *StatusPoller) notify(ctx context.Context, states map[string]missioncontrol.ServiceCreationState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for serviceId, waiters := range p.waiters {
+		state, ok := states[serviceId]
+		if !ok {
+			continue
+		}
+		tflog.Debug(ctx, fmt.Sprintf("Broker status %s: %s", serviceId, state))
+		for _, w := range waiters {
+			// replace a state the waiter has not picked up yet
+			select {
+			case <-w:
+			default:
+			}
+			w <- state
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"terraform-provider-gsolaceclustermgr/internal/fakeserver"
+	"terraform-provider-gsolaceclustermgr/internal/missioncontrol"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusPollerSharesScans(t *testing.T) {
+	var scans atomic.Int32
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/missionControl/eventBrokerServices", r.URL.Path, "only the list is polled")
+		state := "PENDING"
+		if scans.Add(1) > 2 {
+			state = "COMPLETED"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"data":[{"id":"svc1","creationState":"%s"},{"id":"svc2","creationState":"%s"},{"id":"svc3","creationState":"FAILED"}]}`, state, state)
+	}))
+	defer svr.Close()
+
+	client, err := missioncontrol.NewClientWithResponses(svr.URL)
+	assert.NoError(t, err)
+	poller := NewStatusPoller(client, 10*time.Millisecond)
+
+	var wg sync.WaitGroup
+	results := map[string]missioncontrol.ServiceCreationState{}
+	var mu sync.Mutex
+	for _, id := range []string{"svc1", "svc2", "svc3"} {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			state, err := poller.WaitForCreation(context.Background(), id)
+			assert.NoError(t, err)
+			mu.Lock()
+			results[id] = state
+			mu.Unlock()
+		}(id)
+	}
+	wg.Wait()
+
+	assert.Equal(t, missioncontrol.ServiceCreationStateCOMPLETED, results["svc1"])
+	assert.Equal(t, missioncontrol.ServiceCreationStateCOMPLETED, results["svc2"])
+	assert.Equal(t, missioncontrol.ServiceCreationStateFAILED, results["svc3"])
+	assert.LessOrEqual(t, scans.Load(), int32(4), "all brokers share one scan per interval")
+}
+
+func TestStatusPollerTimeout(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"id":"svc1","creationState":"INPROGRESS"}]}`))
+	}))
+	defer svr.Close()
+
+	client, err := missioncontrol.NewClientWithResponses(svr.URL)
+	assert.NoError(t, err)
+	poller := NewStatusPoller(client, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = poller.WaitForCreation(ctx, "svc1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	poller.mu.Lock()
+	defer poller.mu.Unlock()
+	assert.Empty(t, poller.waiters)
+}
+
+func TestStatusPollerScansFakeserverPages(t *testing.T) {
+	// more services than fit on one page, the ones waited for are on the second page
+	objects := map[string]fakeserver.ServiceInfo{}
+	for i := 0; i < 150; i++ {
+		id := fmt.Sprintf("svc%03d", i)
+		objects[id] = fakeserver.ServiceInfo{ID: id, Name: "broker-" + id, State: "COMPLETED", Created: time.Now().Add(-time.Hour)}
+	}
+	objects["svc120"] = fakeserver.ServiceInfo{ID: "svc120", Name: "broker-svc120", State: "PENDING", Created: time.Now().Add(-time.Minute)}
+	objects["svc130"] = fakeserver.ServiceInfo{ID: "svc130", Name: "failing-svc130", State: "PENDING", Created: time.Now().Add(-time.Minute)}
+	fake := fakeserver.NewFakeServer(0, objects, false, false, 0)
+
+	var pages sync.Map
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/missionControl/eventBrokerServices", r.URL.Path, "only the list is polled")
+		pages.Store(r.URL.Query().Get("pageNumber"), true)
+		fake.GetServer().Handler.ServeHTTP(w, r)
+	}))
+	defer svr.Close()
+
+	client, err := missioncontrol.NewClientWithResponses(svr.URL, missioncontrol.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer test")
+		return nil
+	}))
+	assert.NoError(t, err)
+	poller := NewStatusPoller(client, 10*time.Millisecond)
+
+	state, err := poller.WaitForCreation(context.Background(), "svc120")
+	assert.NoError(t, err)
+	assert.Equal(t, missioncontrol.ServiceCreationStateCOMPLETED, state)
+	state, err = poller.WaitForCreation(context.Background(), "svc130")
+	assert.NoError(t, err)
+	assert.Equal(t, missioncontrol.ServiceCreationStateFAILED, state)
+
+	_, page2 := pages.Load("2")
+	assert.True(t, page2, "the scan reads the following pages")
+	_, page3 := pages.Load("3")
+	assert.False(t, page3, "the scan stops after the last page")
+}
+
+func TestStatusPollerScanEndsWithoutNextPage(t *testing.T) {
+	// full pages of other services, without nextPage or always announcing one
+	fullPage := make([]string, statusPollerPageSize)
+	for i := range fullPage {
+		fullPage[i] = fmt.Sprintf(`{"id":"other%d","creationState":"COMPLETED"}`, i)
+	}
+	var announceNextPage atomic.Bool
+	var calls atomic.Int32
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		meta := `{}`
+		if announceNextPage.Load() {
+			meta = `{"pagination":{"nextPage":2}}`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"data":[%s],"meta":%s}`, strings.Join(fullPage, ","), meta)
+	}))
+	defer svr.Close()
+
+	client, err := missioncontrol.NewClientWithResponses(svr.URL)
+	assert.NoError(t, err)
+	poller := NewStatusPoller(client, 10*time.Millisecond)
+	poller.waiters["svc1"] = nil
+
+	_, err = poller.scan(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load(), "the page without nextPage is the last one")
+
+	calls.Store(0)
+	announceNextPage.Store(true)
+	_, err = poller.scan(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(statusPollerMaxPages), calls.Load(), "the pages of a scan are bounded")
+}
@@ -0,0 +1,153 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// AuditEntry is one line of the audit log.
+type AuditEntry struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Method      string    `json:"method"`
+	Path        string    `json:"path"`
+	ServiceId   string    `json:"service_id,omitempty"`
+	OperationId string    `json:"operation_id,omitempty"`
+	Status      int       `json:"status"`
+	DurationMs  int64     `json:"duration_ms"`
+	RequestBody string    `json:"request_body,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// AuditLog appends JSON lines to a file, it is safe for concurrent use.
+type AuditLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewAuditLog checks that the file can be written, so a broken audit configuration fails before anything is changed.
+func NewAuditLog(path string) (*AuditLog, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open audit log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("cannot open audit log: %w", err)
+	}
+	return &AuditLog{path: path}, nil
+}
+
+// Append writes one entry as a single line.
+func (l *AuditLog) Append(entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+var servicePathPattern = regexp.MustCompile(`/eventBrokerServices/([^/]+)`)
+
+// auditResponse covers the ids of OperationResponse and ServiceResponse
+type auditResponse struct {
+	Data struct {
+		Id                  string   `json:"id"`
+		ResourceId          string   `json:"resourceId"`
+		OngoingOperationIds []string `json:"ongoingOperationIds"`
+	} `json:"data"`
+}
+
+// AuditTransport records every mutating call (POST, PATCH, PUT, DELETE) in the audit log.
+// It should be the outermost transport, so retries are recorded as one call.
+type AuditTransport struct {
+	Next http.RoundTripper
+	Log  *AuditLog
+}
+
+func (t *AuditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.Next.RoundTrip(req)
+	}
+
+	entry := AuditEntry{
+		Timestamp: time.Now().UTC(),
+		Method:    req.Method,
+		Path:      req.URL.Path,
+	}
+	if m := servicePathPattern.FindStringSubmatch(req.URL.Path); m != nil {
+		entry.ServiceId = m[1]
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ := io.ReadAll(body)
+			body.Close()
+			entry.RequestBody = RedactBody(reqBody)
+		}
+	}
+
+	resp, err := t.Next.RoundTrip(req)
+	entry.DurationMs = time.Since(entry.Timestamp).Milliseconds()
+	if err != nil {
+		entry.Error = err.Error()
+		t.append(req, entry)
+		return resp, err
+	}
+	entry.Status = resp.StatusCode
+
+	// buffer the body to extract the ids, the client still reads it
+	respBody, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if readErr != nil {
+		entry.Error = readErr.Error()
+		t.append(req, entry)
+		return resp, readErr
+	}
+
+	var parsed auditResponse
+	if json.Unmarshal(respBody, &parsed) == nil {
+		switch {
+		case parsed.Data.ResourceId != "":
+			// OperationResponse of create and delete
+			entry.ServiceId = parsed.Data.ResourceId
+			entry.OperationId = parsed.Data.Id
+		case len(parsed.Data.OngoingOperationIds) > 0:
+			// ServiceResponse of update
+			entry.ServiceId = parsed.Data.Id
+			entry.OperationId = parsed.Data.OngoingOperationIds[len(parsed.Data.OngoingOperationIds)-1]
+		}
+	}
+	t.append(req, entry)
+	return resp, nil
+}
+
+// append does not fail the API call, the change has already been made
+func (t *AuditTransport) append(req *http.Request, entry AuditEntry) {
+	if err := t.Log.Append(entry); err != nil {
+		tflog.Error(req.Context(), "Could not write audit log: "+err.Error(), map[string]interface{}{
+			"http_method": entry.Method,
+			"http_path":   entry.Path,
+		})
+	}
+}
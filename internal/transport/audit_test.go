@@ -0,0 +1,72 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditTransport(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"data":{"id":"op1","resourceId":"svc1","status":"PENDING"}}`))
+		case http.MethodPatch:
+			_, _ = w.Write([]byte(`{"data":{"id":"svc1","ongoingOperationIds":["op2"]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	defer svr.Close()
+
+	logFile := filepath.Join(t.TempDir(), "audit.log")
+	auditLog, err := NewAuditLog(logFile)
+	assert.NoError(t, err)
+	client := &http.Client{Transport: &AuditTransport{Next: http.DefaultTransport, Log: auditLog}}
+
+	resp, err := client.Post(svr.URL+"/api/v2/missionControl/eventBrokerServices", "application/json",
+		strings.NewReader(`{"name":"broker1","password":"geheim"}`))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	resp, err = client.Get(svr.URL + "/api/v2/missionControl/eventBrokerServices/svc1")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	req, _ := http.NewRequest(http.MethodPatch, svr.URL+"/api/v2/missionControl/eventBrokerServices/svc1", strings.NewReader(`{"name":"broker2"}`))
+	resp, err = client.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	content, err := os.ReadFile(logFile)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Len(t, lines, 2, "GET requests are not audited")
+
+	var created, updated AuditEntry
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &created))
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &updated))
+
+	assert.Equal(t, http.MethodPost, created.Method)
+	assert.Equal(t, "/api/v2/missionControl/eventBrokerServices", created.Path)
+	assert.Equal(t, "svc1", created.ServiceId)
+	assert.Equal(t, "op1", created.OperationId)
+	assert.Equal(t, http.StatusAccepted, created.Status)
+	assert.Contains(t, created.RequestBody, "broker1")
+	assert.NotContains(t, created.RequestBody, "geheim")
+
+	assert.Equal(t, http.MethodPatch, updated.Method)
+	assert.Equal(t, "svc1", updated.ServiceId)
+	assert.Equal(t, "op2", updated.OperationId)
+	assert.Equal(t, http.StatusOK, updated.Status)
+}
+
+func TestNewAuditLogInvalidPath(t *testing.T) {
+	_, err := NewAuditLog(filepath.Join(t.TempDir(), "missing", "audit.log"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,146 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DeprecationNotice is a deprecation or sunset hint of the API for one endpoint.
+type DeprecationNotice struct {
+	// Endpoint is the method and the path with ids replaced, e.g. GET /api/v2/missionControl/eventBrokerServices/{id}
+	Endpoint string
+	Detail   string
+}
+
+// DeprecationNotices collects the notices of one provider run, every notice is reported once.
+type DeprecationNotices struct {
+	mu      sync.Mutex
+	seen    map[DeprecationNotice]bool
+	pending []DeprecationNotice
+}
+
+func NewDeprecationNotices() *DeprecationNotices {
+	return &DeprecationNotices{seen: map[DeprecationNotice]bool{}}
+}
+
+// Add records a notice unless it has been seen before.
+func (n *DeprecationNotices) Add(notice DeprecationNotice) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.seen[notice] {
+		return
+	}
+	n.seen[notice] = true
+	n.pending = append(n.pending, notice)
+}
+
+// Drain returns the notices that have not been reported yet.
+func (n *DeprecationNotices) Drain() []DeprecationNotice {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	result := n.pending
+	n.pending = nil
+	return result
+}
+
+// path segments followed by an id
+var idCollections = map[string]bool{
+	"datacenters":         true,
+	"environments":        true,
+	"eventBrokerServices": true,
+	"clientProfiles":      true,
+	"operations":          true,
+	"serverCertificates":  true,
+	"organizations":       true,
+	"serviceClasses":      true,
+}
+
+// endpointName replaces the ids in the path, so all services share one notice per endpoint
+func endpointName(req *http.Request) string {
+	segments := strings.Split(req.URL.Path, "/")
+	for i := 1; i < len(segments); i++ {
+		if idCollections[segments[i-1]] && segments[i] != "" {
+			segments[i] = "{id}"
+		}
+	}
+	return req.Method + " " + strings.Join(segments, "/")
+}
+
+// DeprecationTransport watches the responses for Deprecation, Sunset and Warning headers
+// and for deprecation hints in the meta object of JSON bodies.
+type DeprecationTransport struct {
+	Next    http.RoundTripper
+	Notices *DeprecationNotices
+}
+
+func (t *DeprecationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	endpoint := endpointName(req)
+
+	if deprecation := resp.Header.Get("Deprecation"); deprecation != "" {
+		detail := "deprecated"
+		if deprecation != "true" && deprecation != "?1" {
+			detail = "deprecated since " + deprecation
+		}
+		if link := resp.Header.Get("Link"); link != "" {
+			detail += ", see " + link
+		}
+		t.Notices.Add(DeprecationNotice{Endpoint: endpoint, Detail: detail})
+	}
+	if sunset := resp.Header.Get("Sunset"); sunset != "" {
+		t.Notices.Add(DeprecationNotice{Endpoint: endpoint, Detail: "will be removed on " + sunset})
+	}
+	for _, warning := range resp.Header.Values("Warning") {
+		t.Notices.Add(DeprecationNotice{Endpoint: endpoint, Detail: warning})
+	}
+
+	if !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return resp, nil
+	}
+	// buffer the body to look for meta hints, the client still reads it
+	body, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if readErr != nil {
+		return resp, readErr
+	}
+	var parsed struct {
+		Meta map[string]interface{} `json:"meta"`
+	}
+	if json.Unmarshal(body, &parsed) == nil {
+		for _, detail := range metaDeprecations("meta", parsed.Meta) {
+			t.Notices.Add(DeprecationNotice{Endpoint: endpoint, Detail: detail})
+		}
+	}
+	return resp, nil
+}
+
+// metaDeprecations returns the meta entries with deprecat or sunset in their key, e.g. meta.deprecation.fields
+func metaDeprecations(prefix string, meta map[string]interface{}) []string {
+	var result []string
+	for key, value := range meta {
+		lowerKey := strings.ToLower(key)
+		if strings.Contains(lowerKey, "deprecat") || strings.Contains(lowerKey, "sunset") {
+			detail, err := json.Marshal(value)
+			if err != nil {
+				detail = []byte(fmt.Sprint(value))
+			}
+			result = append(result, fmt.Sprintf("%s.%s: %s", prefix, key, detail))
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			result = append(result, metaDeprecations(prefix+"."+key, nested)...)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,48 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecationTransport(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v2/missionControl/datacenters" {
+			_, _ = w.Write([]byte(`{"data":[],"meta":{"pagination":{"count":0}}}`))
+			return
+		}
+		w.Header().Set("Deprecation", "@1735689600")
+		w.Header().Set("Sunset", "Wed, 31 Dec 2025 23:59:59 GMT")
+		w.Header().Add("Warning", `299 - "field msgVpnName is deprecated"`)
+		_, _ = w.Write([]byte(`{"data":{"id":"svc"},"meta":{"deprecation":{"fields":["cluster.name"]}}}`))
+	}))
+	defer svr.Close()
+
+	notices := NewDeprecationNotices()
+	client := &http.Client{Transport: &DeprecationTransport{Next: http.DefaultTransport, Notices: notices}}
+
+	for _, id := range []string{"svc1", "svc2"} {
+		resp, err := client.Get(svr.URL + "/api/v2/missionControl/eventBrokerServices/" + id)
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Contains(t, string(body), `"id":"svc"`, "the body must still be readable")
+	}
+	resp, err := client.Get(svr.URL + "/api/v2/missionControl/datacenters")
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	endpoint := "GET /api/v2/missionControl/eventBrokerServices/{id}"
+	assert.ElementsMatch(t, []DeprecationNotice{
+		{Endpoint: endpoint, Detail: "deprecated since @1735689600"},
+		{Endpoint: endpoint, Detail: "will be removed on Wed, 31 Dec 2025 23:59:59 GMT"},
+		{Endpoint: endpoint, Detail: `299 - "field msgVpnName is deprecated"`},
+		{Endpoint: endpoint, Detail: `meta.deprecation: {"fields":["cluster.name"]}`},
+	}, notices.Drain(), "every notice is reported once for both services")
+	assert.Empty(t, notices.Drain())
+}
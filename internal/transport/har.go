@@ -0,0 +1,248 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// HAR 1.2 types, see http://www.softwareishard.com/blog/har-12-spec/
+
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	// custom field for requests without response, e.g. timeouts
+	Error string `json:"_error,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// closes the entries array and the HAR object after the last entry
+const harTrailer = "\n    ]\n  }\n}\n"
+
+// HARRecorder writes the API traffic to a HAR 1.2 file as it arrives. The file stays open, every entry
+// overwrites the closing brackets and closes the JSON again, so the file is valid after every call and
+// the cost of a write does not grow with the file.
+type HARRecorder struct {
+	mu      sync.Mutex
+	file    *os.File
+	end     int64 // offset of the trailer
+	entries int
+}
+
+// NewHARRecorder writes an empty HAR file, so a wrong path fails before any API call.
+func NewHARRecorder(path string, creatorVersion string) (*HARRecorder, error) {
+	content, err := json.MarshalIndent(harFile{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "terraform-provider-gsolaceclustermgr", Version: creatorVersion},
+		Entries: []harEntry{},
+	}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	// cut the empty entries array open
+	head := strings.TrimSuffix(string(content), "[]\n  }\n}") + "["
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("cannot write http trace file: %w", err)
+	}
+	if _, err := file.WriteString(head + harTrailer); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("cannot write http trace file: %w", err)
+	}
+	return &HARRecorder{file: file, end: int64(len(head))}, nil
+}
+
+// add writes the entry before the trailer, a failed write is overwritten by the next one
+func (r *HARRecorder) add(entry harEntry) error {
+	content, err := json.MarshalIndent(entry, "      ", "  ")
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	separator := "\n      "
+	if r.entries > 0 {
+		separator = "," + separator
+	}
+	chunk := separator + string(content)
+	if _, err := r.file.WriteAt([]byte(chunk+harTrailer), r.end); err != nil {
+		return err
+	}
+	r.end += int64(len(chunk))
+	r.entries++
+	return nil
+}
+
+// HARTransport records every request attempt with credentials redacted.
+type HARTransport struct {
+	Next     http.RoundTripper
+	Recorder *HARRecorder
+}
+
+func (t *HARTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	entry := harEntry{
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         req.URL.String(),
+			HTTPVersion: req.Proto,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(RedactHeaders(req.Header)),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    0,
+		},
+		Response: harResponse{
+			Cookies:     []harNameValue{},
+			Headers:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+	}
+	for name, values := range req.URL.Query() {
+		for _, value := range values {
+			entry.Request.QueryString = append(entry.Request.QueryString, harNameValue{Name: name, Value: value})
+		}
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ := io.ReadAll(body)
+			body.Close()
+			entry.Request.BodySize = len(reqBody)
+			entry.Request.PostData = &harPostData{MimeType: req.Header.Get("Content-Type"), Text: RedactBody(reqBody)}
+		}
+	}
+
+	start := time.Now()
+	entry.StartedDateTime = start.Format(time.RFC3339Nano)
+	resp, err := t.Next.RoundTrip(req)
+	if err != nil {
+		entry.Time = msSince(start)
+		entry.Timings.Wait = entry.Time
+		entry.Error = err.Error()
+		t.add(req, entry)
+		return resp, err
+	}
+	wait := msSince(start)
+
+	// buffer the body so it can be recorded and still be read by the client
+	respBody, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	entry.Time = msSince(start)
+	entry.Timings.Wait = wait
+	entry.Timings.Receive = entry.Time - wait
+	entry.Response.Status = resp.StatusCode
+	entry.Response.StatusText = http.StatusText(resp.StatusCode)
+	entry.Response.HTTPVersion = resp.Proto
+	entry.Response.Headers = harHeaders(RedactHeaders(resp.Header))
+	entry.Response.BodySize = len(respBody)
+	entry.Response.Content = harContent{
+		Size:     len(respBody),
+		MimeType: resp.Header.Get("Content-Type"),
+		Text:     RedactBody(respBody),
+	}
+	if readErr != nil {
+		entry.Error = readErr.Error()
+	}
+	t.add(req, entry)
+	return resp, readErr
+}
+
+// add does not fail the API call, tracing is best effort once the file has been created
+func (t *HARTransport) add(req *http.Request, entry harEntry) {
+	if err := t.Recorder.add(entry); err != nil {
+		tflog.Error(req.Context(), "Could not write http trace file: "+err.Error(), map[string]interface{}{
+			"http_method": req.Method,
+			"http_url":    req.URL.String(),
+		})
+	}
+}
+
+func harHeaders(h http.Header) []harNameValue {
+	result := []harNameValue{}
+	for name, values := range h {
+		for _, value := range values {
+			result = append(result, harNameValue{Name: name, Value: value})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func msSince(start time.Time) float64 {
+	return float64(time.Since(start).Microseconds()) / 1000
+}
@@ -0,0 +1,81 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHARTransport(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`<ErrorDTO><message>bad</message><password>geheim</password></ErrorDTO>`))
+	}))
+	defer svr.Close()
+
+	traceFile := filepath.Join(t.TempDir(), "trace.har")
+	recorder, err := NewHARRecorder(traceFile, "test")
+	assert.NoError(t, err)
+
+	var har harFile
+	content, err := os.ReadFile(traceFile)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(content, &har))
+	assert.Equal(t, "1.2", har.Log.Version)
+	assert.Empty(t, har.Log.Entries)
+
+	client := &http.Client{Transport: &HARTransport{Next: http.DefaultTransport, Recorder: recorder}}
+	req, _ := http.NewRequest(http.MethodPost, svr.URL+"/api/v2/missionControl/eventBrokerServices?x=1", strings.NewReader(`{"name":"broker1","token":"abc"}`))
+	req.Header.Set("Authorization", "Bearer secret-token")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	content, err = os.ReadFile(traceFile)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "secret-token")
+	assert.NotContains(t, string(content), "geheim")
+	assert.NoError(t, json.Unmarshal(content, &har))
+	assert.Len(t, har.Log.Entries, 1)
+
+	entry := har.Log.Entries[0]
+	assert.Equal(t, http.MethodPost, entry.Request.Method)
+	assert.Equal(t, []harNameValue{{Name: "x", Value: "1"}}, entry.Request.QueryString)
+	assert.Equal(t, `{"name":"broker1","token":"REDACTED"}`, entry.Request.PostData.Text)
+	assert.Equal(t, http.StatusBadRequest, entry.Response.Status)
+	assert.Equal(t, "application/xml", entry.Response.Content.MimeType)
+	assert.Contains(t, entry.Response.Content.Text, "<message>bad</message>")
+}
+
+func TestHARRecorderWritesEveryEntry(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer svr.Close()
+
+	traceFile := filepath.Join(t.TempDir(), "trace.har")
+	recorder, err := NewHARRecorder(traceFile, "test")
+	assert.NoError(t, err)
+	client := &http.Client{Transport: &HARTransport{Next: http.DefaultTransport, Recorder: recorder}}
+
+	// calls in quick succession are on disk once RoundTrip returns
+	for i := 1; i <= 3; i++ {
+		resp, err := client.Get(svr.URL)
+		assert.NoError(t, err)
+		resp.Body.Close()
+
+		var har harFile
+		content, err := os.ReadFile(traceFile)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(content, &har), "the file is valid after every call")
+		assert.Len(t, har.Log.Entries, i)
+	}
+}
@@ -0,0 +1,67 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// LogSubsystem is the tflog subsystem of the API traffic, its level can be set with TF_LOG_PROVIDER_MISSIONCONTROL_HTTP
+const LogSubsystem = "missioncontrol_http"
+
+// LoggingTransport logs every request and response with credentials redacted.
+type LoggingTransport struct {
+	Next http.RoundTripper
+}
+
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := tflog.NewSubsystem(req.Context(), LogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_MISSIONCONTROL_HTTP"))
+
+	var reqBody []byte
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ = io.ReadAll(body)
+			body.Close()
+		}
+	}
+	tflog.SubsystemDebug(ctx, LogSubsystem, "Sending MissionControl API request", map[string]interface{}{
+		"http_method":          req.Method,
+		"http_url":             req.URL.String(),
+		"http_request_headers": RedactHeaders(req.Header),
+		"http_request_body":    RedactBody(reqBody),
+	})
+
+	start := time.Now()
+	resp, err := t.Next.RoundTrip(req)
+	duration := time.Since(start)
+	if err != nil {
+		tflog.SubsystemError(ctx, LogSubsystem, "MissionControl API request failed", map[string]interface{}{
+			"http_method": req.Method,
+			"http_url":    req.URL.String(),
+			"error":       err.Error(),
+			"duration_ms": duration.Milliseconds(),
+		})
+		return resp, err
+	}
+
+	// buffer the body so it can be logged and still be read by the client
+	respBody, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if readErr != nil {
+		return resp, readErr
+	}
+
+	tflog.SubsystemDebug(ctx, LogSubsystem, "Received MissionControl API response", map[string]interface{}{
+		"http_method":           req.Method,
+		"http_url":              req.URL.String(),
+		"http_status":           resp.StatusCode,
+		"http_response_headers": RedactHeaders(resp.Header),
+		"http_response_body":    RedactBody(respBody),
+		"duration_ms":           duration.Milliseconds(),
+	})
+	return resp, nil
+}
@@ -0,0 +1,85 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	DefaultRequestsPerSecond = 5.0
+	DefaultBurst             = 10
+)
+
+// RateLimiter is a token bucket shared by all MissionControl calls of one provider instance.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a limiter that starts with a full bucket.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Reserve takes a token and returns how long the caller has to wait before using it.
+func (l *RateLimiter) Reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// Release hands back a reserved token that has not been used (e.g. when the caller gave up waiting).
+func (l *RateLimiter) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens++
+}
+
+// RateLimitTransport delays requests until the shared RateLimiter grants a token.
+type RateLimitTransport struct {
+	Next    http.RoundTripper
+	Limiter *RateLimiter
+}
+
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if wait := t.Limiter.Reserve(); wait > 0 {
+		tflog.Info(ctx, fmt.Sprintf("Client side rate limit reached, delaying %s %s by %s", req.Method, req.URL.Path, wait.Round(time.Millisecond)))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			t.Limiter.Release()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return t.Next.RoundTrip(req)
+}
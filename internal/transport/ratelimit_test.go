@@ -0,0 +1,25 @@
+package transport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterBurst(t *testing.T) {
+	l := NewRateLimiter(10, 3)
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, time.Duration(0), l.Reserve(), "burst token %d", i)
+	}
+	wait := l.Reserve()
+	assert.InDelta(t, float64(100*time.Millisecond), float64(wait), float64(10*time.Millisecond))
+
+	// a second waiter queues behind the first one
+	wait = l.Reserve()
+	assert.InDelta(t, float64(200*time.Millisecond), float64(wait), float64(10*time.Millisecond))
+
+	l.Release()
+	wait = l.Reserve()
+	assert.InDelta(t, float64(200*time.Millisecond), float64(wait), float64(10*time.Millisecond))
+}
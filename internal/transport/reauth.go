@@ -0,0 +1,50 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"terraform-provider-gsolaceclustermgr/internal/credentials"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ReauthTransport re-fetches the bearer token once when the server answers with 401,
+// e.g. because a rotated or expiring token has been replaced mid-apply.
+type ReauthTransport struct {
+	Next   http.RoundTripper
+	Tokens credentials.TokenSource
+}
+
+func (t *ReauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !canRewind(req) {
+		return resp, err
+	}
+
+	ctx := req.Context()
+	t.Tokens.Invalidate()
+	token, tokenErr := t.Tokens.Token(ctx)
+	if tokenErr != nil {
+		tflog.Warn(ctx, "Could not refresh bearer token after 401: "+tokenErr.Error())
+		return resp, nil
+	}
+	if req.Header.Get("Authorization") == "Bearer "+token {
+		// nothing changed, the token is just not valid
+		return resp, nil
+	}
+
+	tflog.Info(ctx, "Bearer token has been refreshed after 401, repeating request")
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	retryReq := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retryReq.Body = body
+	}
+	retryReq.Header.Set("Authorization", "Bearer "+token)
+	return t.Next.RoundTrip(retryReq)
+}
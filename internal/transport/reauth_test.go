@@ -0,0 +1,47 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rotatingTokens hands out token1 until invalidated, then token2
+type rotatingTokens struct {
+	invalidated bool
+}
+
+func (r *rotatingTokens) Token(_ context.Context) (string, error) {
+	if r.invalidated {
+		return "token2", nil
+	}
+	return "token1", nil
+}
+
+func (r *rotatingTokens) Invalidate() {
+	r.invalidated = true
+}
+
+func TestReauthOn401(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer svr.Close()
+
+	tokens := &rotatingTokens{}
+	client := &http.Client{Transport: &ReauthTransport{Next: http.DefaultTransport, Tokens: tokens}}
+
+	req, _ := http.NewRequest(http.MethodGet, svr.URL, nil)
+	req.Header.Set("Authorization", "Bearer token1")
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, tokens.invalidated)
+}
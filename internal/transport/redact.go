@@ -0,0 +1,94 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const redacted = "REDACTED"
+
+// keys (lower case) whose values are never logged
+var sensitiveKeys = map[string]bool{
+	"password":     true,
+	"token":        true,
+	"accesstoken":  true,
+	"refreshtoken": true,
+	"privatekey":   true,
+	"passphrase":   true,
+	"secret":       true,
+	"clientsecret": true,
+	"apikey":       true,
+}
+
+// headers whose values are never logged
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+var (
+	privateKeyPattern = regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`)
+	// key/value pairs in non JSON bodies, e.g. the XML ErrorDTO or form data
+	xmlValuePattern  = regexp.MustCompile(`(?is)<(password|token|privateKey|passphrase|secret)>.*?</(password|token|privateKey|passphrase|secret)>`)
+	textValuePattern = regexp.MustCompile(`(?i)("?(?:password|token|privateKey|passphrase|secret)"?\s*[:=]\s*"?)[^"&,\s}]+`)
+)
+
+// RedactHeaders returns a copy of the headers with credentials replaced.
+func RedactHeaders(h http.Header) http.Header {
+	result := h.Clone()
+	for _, name := range sensitiveHeaders {
+		if values := result.Values(name); len(values) > 0 {
+			for i, v := range values {
+				// keep the auth scheme, it helps when debugging
+				if scheme, _, found := strings.Cut(v, " "); found && name != "Cookie" && name != "Set-Cookie" {
+					values[i] = scheme + " " + redacted
+				} else {
+					values[i] = redacted
+				}
+			}
+		}
+	}
+	return result
+}
+
+// RedactBody replaces tokens, passwords, private keys and SEMP credentials in a request or response body.
+func RedactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var parsed interface{}
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		if b, err := json.Marshal(redactValue(parsed, false)); err == nil {
+			return string(b)
+		}
+	}
+	s := privateKeyPattern.ReplaceAllString(string(body), redacted)
+	s = xmlValuePattern.ReplaceAllString(s, "<$1>"+redacted+"</$2>")
+	return textValuePattern.ReplaceAllString(s, "${1}"+redacted)
+}
+
+// redactValue walks a decoded JSON value, all string leaves of credential objects are redacted
+func redactValue(v interface{}, inCredential bool) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, child := range value {
+			key := strings.ToLower(k)
+			if sensitiveKeys[key] {
+				value[k] = redacted
+				continue
+			}
+			value[k] = redactValue(child, inCredential || strings.HasSuffix(key, "credential") || strings.HasSuffix(key, "credentials"))
+		}
+		return value
+	case []interface{}:
+		for i, child := range value {
+			value[i] = redactValue(child, inCredential)
+		}
+		return value
+	case string:
+		if inCredential {
+			return redacted
+		}
+		return privateKeyPattern.ReplaceAllString(value, redacted)
+	}
+	return v
+}
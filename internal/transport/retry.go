@@ -0,0 +1,199 @@
+/*
+Package transport contains the http.RoundTripper chain used by the MissionControl client.
+*/
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	DefaultMaxRetries     = 4
+	DefaultRequestTimeout = 60 * time.Second
+	defaultMinBackoff     = 1 * time.Second
+	defaultMaxBackoff     = 30 * time.Second
+)
+
+// RetryTransport retries failed requests with exponential backoff, jitter and Retry-After support.
+// Idempotent requests are retried on network errors and on 429/502/503/504 responses.
+// Non-idempotent requests (POST, PATCH) are only retried when the server signals that the request
+// was not processed at all (429, 503 with Retry-After), a retried create could bill a second broker.
+type RetryTransport struct {
+	Next           http.RoundTripper
+	MaxRetries     int
+	RequestTimeout time.Duration // per attempt, 0 means no timeout
+	MinBackoff     time.Duration
+	MaxBackoff     time.Duration
+}
+
+// NewRetryTransport wraps next with the default backoff settings.
+func NewRetryTransport(next http.RoundTripper, maxRetries int, requestTimeout time.Duration) *RetryTransport {
+	return &RetryTransport{
+		Next:           next,
+		MaxRetries:     maxRetries,
+		RequestTimeout: requestTimeout,
+		MinBackoff:     defaultMinBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+	}
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		attemptReq, cancel, err := t.attemptRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.next().RoundTrip(attemptReq)
+
+		if attempt >= t.MaxRetries || ctx.Err() != nil || !canRewind(req) || !shouldRetry(req.Method, resp, err) {
+			if resp != nil {
+				resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			} else {
+				cancel()
+			}
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		var reason string
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+			// drain so the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		cancel()
+
+		tflog.Warn(ctx, fmt.Sprintf("Retrying %s %s in %s (attempt %d of %d): %s", req.Method, req.URL.Path, wait, attempt+1, t.MaxRetries, reason))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (t *RetryTransport) next() http.RoundTripper {
+	if t.Next == nil {
+		return http.DefaultTransport
+	}
+	return t.Next
+}
+
+// attemptRequest clones the request for one attempt, rewinding the body and applying the per attempt timeout
+func (t *RetryTransport) attemptRequest(req *http.Request, attempt int) (*http.Request, context.CancelFunc, error) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if t.RequestTimeout > 0 {
+		ctx, cancel = context.WithTimeout(req.Context(), t.RequestTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(req.Context())
+	}
+	attemptReq := req.Clone(ctx)
+	if attempt > 0 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, nil, err
+		}
+		attemptReq.Body = body
+	}
+	return attemptReq, cancel, nil
+}
+
+// backoff computes the wait before the next attempt, the server's Retry-After wins over our own backoff
+func (t *RetryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return wait
+		}
+	}
+	wait := t.MinBackoff << attempt
+	if wait <= 0 || wait > t.MaxBackoff {
+		wait = t.MaxBackoff
+	}
+	// equal jitter: half fixed, half random
+	half := wait / 2
+	if half <= 0 {
+		return wait
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1)) // #nosec G404 -- jitter does not need crypto rand
+}
+
+func shouldRetry(method string, resp *http.Response, err error) bool {
+	idempotent := isIdempotent(method)
+	if err != nil {
+		// we cannot know whether a non-idempotent request reached the server
+		return idempotent
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusServiceUnavailable:
+		// a 503 of a proxy may come after the request reached the API, Retry-After is sent by the API itself
+		return idempotent || resp.Header.Get("Retry-After") != ""
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func canRewind(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// parseRetryAfter supports both the delay-seconds and the HTTP-date format
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// cancelOnClose releases the per attempt context once the response body has been consumed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
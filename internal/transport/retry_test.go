@@ -0,0 +1,100 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test server answering with the given status codes in order, then 200
+func statusSequenceServer(codes ...int) (*httptest.Server, *int32) {
+	var calls int32
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if int(n) <= len(codes) {
+			w.WriteHeader(codes[n-1])
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	return svr, &calls
+}
+
+func testRetryClient(maxRetries int) *http.Client {
+	rt := NewRetryTransport(http.DefaultTransport, maxRetries, 5*time.Second)
+	rt.MinBackoff = time.Millisecond
+	rt.MaxBackoff = 5 * time.Millisecond
+	return &http.Client{Transport: rt}
+}
+
+func TestRetryIdempotent(t *testing.T) {
+	svr, calls := statusSequenceServer(503, 502, 429)
+	defer svr.Close()
+
+	resp, err := testRetryClient(4).Get(svr.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(4), atomic.LoadInt32(calls))
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	svr, calls := statusSequenceServer(503, 503, 503)
+	defer svr.Close()
+
+	resp, err := testRetryClient(1).Get(svr.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestRetryPostConservative(t *testing.T) {
+	svr, calls := statusSequenceServer(502)
+	defer svr.Close()
+
+	resp, err := testRetryClient(4).Post(svr.URL, "application/json", strings.NewReader(`{"name":"x"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode, "502 on POST must not be retried")
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+
+	svr2, calls2 := statusSequenceServer(429, 503)
+	defer svr2.Close()
+
+	resp, err = testRetryClient(4).Post(svr2.URL, "application/json", strings.NewReader(`{"name":"x"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "503 without Retry-After on POST must not be retried")
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls2))
+
+	var calls3 int32
+	svr3 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls3, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer svr3.Close()
+
+	resp, err = testRetryClient(4).Post(svr3.URL, "application/json", strings.NewReader(`{"name":"x"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "503 with Retry-After on POST is retried")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls3))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	wait, ok := parseRetryAfter("7")
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Second, wait)
+
+	wait, ok = parseRetryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), wait)
+
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+}
@@ -0,0 +1,85 @@
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"terraform-provider-gsolaceclustermgr/internal/fakeserver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeSelfSignedCert creates a certificate for 127.0.0.1 and returns the cert and key file
+func writeSelfSignedCert(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fakeserver"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	assert.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certFile, keyFile
+}
+
+func TestBaseTransportTLS(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t)
+
+	svr := fakeserver.NewFakeServer(8092, make(map[string]fakeserver.ServiceInfo), false, false, 0)
+	svr.SetTLS(certFile, keyFile)
+	svr.StartInBackground()
+	defer svr.Shutdown()
+
+	url := "https://127.0.0.1:8092/api/v2/missionControl/eventBrokerServices/unknown"
+
+	// untrusted without the CA bundle
+	base, err := NewBaseTransport(BaseTransportOptions{})
+	assert.NoError(t, err)
+	_, err = (&http.Client{Transport: base}).Get(url)
+	assert.ErrorContains(t, err, "certificate")
+
+	base, err = NewBaseTransport(BaseTransportOptions{CACertFile: certFile})
+	assert.NoError(t, err)
+	resp, err := (&http.Client{Transport: base}).Get(url)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "any http answer proves the TLS handshake worked")
+
+	base, err = NewBaseTransport(BaseTransportOptions{InsecureSkipVerify: true})
+	assert.NoError(t, err)
+	resp, err = (&http.Client{Transport: base}).Get(url)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "any http answer proves the TLS handshake worked")
+}
+
+func TestBaseTransportInvalidOptions(t *testing.T) {
+	_, err := NewBaseTransport(BaseTransportOptions{ProxyURL: "proxy:3128"})
+	assert.Error(t, err)
+
+	_, err = NewBaseTransport(BaseTransportOptions{CACertFile: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,141 @@
+/*
+Package waiter polls long running MissionControl operations until they are done.
+*/
+package waiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// DefaultProgressInterval is how often a still running wait is logged.
+const DefaultProgressInterval = time.Minute
+
+// Config controls the polling of Wait.
+type Config struct {
+	// Interval is the wait before the first refresh and the base of the backoff, 0 refreshes immediately
+	Interval time.Duration
+	// MaxInterval bounds the backoff, 0 means Interval
+	MaxInterval time.Duration
+	// Multiplier grows the interval after every refresh, values <= 1 poll at a constant interval
+	Multiplier float64
+	// Jitter is the random share of every interval, e.g. 0.1 spreads the intervals by +-10%
+	Jitter float64
+	// ProgressInterval is how often progress is logged, 0 means DefaultProgressInterval
+	ProgressInterval time.Duration
+}
+
+// Delay returns the wait before the given refresh, counting from 0.
+func (c Config) Delay(attempt int) time.Duration {
+	wait := float64(c.Interval)
+	maxWait := float64(c.MaxInterval)
+	if maxWait < wait {
+		maxWait = wait
+	}
+	for i := 0; i < attempt && c.Multiplier > 1 && wait < maxWait; i++ {
+		wait *= c.Multiplier
+	}
+	if wait > maxWait {
+		wait = maxWait
+	}
+	if c.Jitter > 0 {
+		wait += wait * c.Jitter * (2*rand.Float64() - 1) // #nosec G404 -- jitter does not need crypto rand
+	}
+	return time.Duration(wait)
+}
+
+// RefreshFunc looks at the operation once. It returns the observed state and whether the wait is over,
+// an error ends the wait.
+type RefreshFunc func(ctx context.Context) (state string, done bool, err error)
+
+// TimeoutError is returned when the context deadline passes before the operation is done.
+type TimeoutError struct {
+	Description string
+	LastState   string
+	Elapsed     time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("timeout after %s %s, %s", FormatElapsed(e.Elapsed), e.Description, lastState(e.LastState))
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// Wait calls refresh until it is done, the context is done or refresh fails. It returns the last observed state.
+// The description completes "still ..." in the progress log, e.g. "creating broker abc".
+func Wait(ctx context.Context, config Config, description string, refresh RefreshFunc) (string, error) {
+	progressInterval := config.ProgressInterval
+	if progressInterval <= 0 {
+		progressInterval = DefaultProgressInterval
+	}
+	start := time.Now()
+	lastProgress := start
+	state := ""
+	for attempt := 0; ; attempt++ {
+		if err := Sleep(ctx, config.Delay(attempt)); err != nil {
+			return state, stopped(ctx, description, state, start)
+		}
+		newState, done, err := refresh(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return state, stopped(ctx, description, state, start)
+			}
+			return state, err
+		}
+		state = newState
+		if done {
+			return state, nil
+		}
+		if time.Since(lastProgress) >= progressInterval {
+			lastProgress = time.Now()
+			tflog.Info(ctx, fmt.Sprintf("still %s, status %s, %s elapsed", description, state, FormatElapsed(time.Since(start))))
+		}
+	}
+}
+
+// Sleep waits for the duration unless the context is done first.
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stopped explains why the context ended the wait
+func stopped(ctx context.Context, description string, state string, start time.Time) error {
+	elapsed := time.Since(start)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &TimeoutError{Description: description, LastState: state, Elapsed: elapsed}
+	}
+	return fmt.Errorf("cancelled after %s %s, %s: %w", FormatElapsed(elapsed), description, lastState(state), ctx.Err())
+}
+
+func lastState(state string) string {
+	if state == "" {
+		return "no status observed yet"
+	}
+	return "last status " + state
+}
+
+// FormatElapsed rounds durations for humans, e.g. 4m or 45s.
+func FormatElapsed(d time.Duration) string {
+	if d >= time.Minute {
+		return strings.TrimSuffix(d.Truncate(time.Minute).String(), "0s")
+	}
+	return d.Round(time.Second).String()
+}
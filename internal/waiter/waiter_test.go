@@ -0,0 +1,82 @@
+package waiter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitDone(t *testing.T) {
+	states := []string{"PENDING", "INPROGRESS", "INPROGRESS", "COMPLETED"}
+	calls := 0
+	state, err := Wait(context.Background(), Config{Interval: time.Millisecond}, "creating broker b1", func(ctx context.Context) (string, bool, error) {
+		state := states[calls]
+		calls++
+		return state, state == "COMPLETED", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "COMPLETED", state)
+	assert.Equal(t, 4, calls)
+}
+
+func TestWaitRefreshError(t *testing.T) {
+	failure := errors.New("boom")
+	state, err := Wait(context.Background(), Config{}, "creating broker b1", func(ctx context.Context) (string, bool, error) {
+		return "", false, failure
+	})
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, "", state)
+}
+
+func TestWaitTimeoutReportsLastState(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	state, err := Wait(ctx, Config{Interval: 5 * time.Millisecond}, "creating broker b1", func(ctx context.Context) (string, bool, error) {
+		return "INPROGRESS", false, nil
+	})
+	assert.Equal(t, "INPROGRESS", state)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	var timeoutErr *TimeoutError
+	if assert.ErrorAs(t, err, &timeoutErr) {
+		assert.Equal(t, "INPROGRESS", timeoutErr.LastState)
+	}
+	assert.Contains(t, err.Error(), "creating broker b1, last status INPROGRESS")
+}
+
+func TestWaitCancelledDuringInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := Wait(ctx, Config{Interval: time.Hour}, "deleting broker b1", func(ctx context.Context) (string, bool, error) {
+		return "", false, nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second, "a cancellation does not wait out the interval")
+	assert.Contains(t, err.Error(), "no status observed yet")
+}
+
+func TestDelayBackoffAndJitter(t *testing.T) {
+	config := Config{Interval: time.Second, MaxInterval: 4 * time.Second, Multiplier: 2}
+	assert.Equal(t, time.Second, config.Delay(0))
+	assert.Equal(t, 2*time.Second, config.Delay(1))
+	assert.Equal(t, 4*time.Second, config.Delay(2))
+	assert.Equal(t, 4*time.Second, config.Delay(10))
+
+	assert.Equal(t, time.Second, Config{Interval: time.Second, Multiplier: 2}.Delay(3), "no backoff without MaxInterval")
+
+	config.Jitter = 0.1
+	for i := 0; i < 100; i++ {
+		delay := config.Delay(1)
+		assert.GreaterOrEqual(t, delay, 1800*time.Millisecond)
+		assert.LessOrEqual(t, delay, 2200*time.Millisecond)
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	assert.Equal(t, "45s", FormatElapsed(45*time.Second+300*time.Millisecond))
+	assert.Equal(t, "4m", FormatElapsed(4*time.Minute+12*time.Second))
+	assert.Equal(t, "1h5m", FormatElapsed(time.Hour+5*time.Minute+59*time.Second))
+}